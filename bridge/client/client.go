@@ -0,0 +1,281 @@
+// Package client drives a Tsyne bridge from Go.
+//
+// A Client either spawns a bridge (StartStdio, StartGrpc) or attaches to one
+// that is already running (DialSocket, DialGrpc). Every message type the
+// bridge understands can be sent with Call; the common windows and widgets
+// also have typed helpers. Events raised by the bridge reach Subscribe
+// channels and OnCallback functions.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ProtocolVersion is the bridge protocol version this client speaks. The
+// bridge refuses the hello handshake if its major version differs or its
+// minor version is older.
+const ProtocolVersion = "2.9"
+
+// eventBuffer is how many events may wait for OnCallback functions and
+// Subscribe channels before further events are dropped
+const eventBuffer = 256
+
+// ErrClosed is returned by calls made after the connection to the bridge ended
+var ErrClosed = errors.New("bridge connection closed")
+
+// Event is an event raised by the bridge, such as a button's "callback"
+type Event struct {
+	Type     string                 `json:"type"`
+	WidgetID string                 `json:"widgetId"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// CallbackID returns the callbackId an event carries, or "" if it has none
+func (e Event) CallbackID() string {
+	id, _ := e.Data["callbackId"].(string)
+	return id
+}
+
+// Error is a request the bridge answered with success false
+type Error struct {
+	Type    string // message type of the failed request
+	Message string
+	Code    string // machine-readable code, e.g. MISSING_FIELD or WIDGET_NOT_FOUND
+	Field   string // payload field the error is about, if any
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s (%s)", e.Type, e.Message, e.Code)
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// response is a bridge response as both transports deliver it
+type response struct {
+	ID      string                 `json:"id"`
+	Success bool                   `json:"success"`
+	Result  map[string]interface{} `json:"result,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+	Field   string                 `json:"field,omitempty"`
+}
+
+// transport carries requests to a bridge and events back from it
+type transport interface {
+	// call sends a request and waits for its response, or for ctx to end,
+	// which cancels the request in the bridge
+	call(ctx context.Context, id, msgType string, payload map[string]interface{}) (response, error)
+	// close ends the connection; the transport then reports it closed
+	close() error
+}
+
+// Client is a connection to one bridge. Its methods may be called from any
+// goroutine, including from OnCallback functions.
+type Client struct {
+	transport transport
+	stop      func() error // quits a spawned bridge and closes the transport, nil when attached
+	stopOnce  sync.Once
+	stopErr   error
+	nextID    atomic.Uint64
+
+	events chan Event
+
+	mu          sync.Mutex
+	callbacks   map[string]func(Event)
+	subscribers map[int]*subscriber
+	nextSub     int
+
+	done      chan struct{}
+	err       error
+	closeOnce sync.Once
+}
+
+// subscriber is one Subscribe channel
+type subscriber struct {
+	types map[string]bool // event types of interest; empty means all types
+	ch    chan Event
+}
+
+func newClient() *Client {
+	c := &Client{
+		events:      make(chan Event, eventBuffer),
+		callbacks:   make(map[string]func(Event)),
+		subscribers: make(map[int]*subscriber),
+		done:        make(chan struct{}),
+	}
+	go c.deliverEvents()
+	return c
+}
+
+// start completes a client once its transport is connected: it performs the
+// hello handshake, and tears the connection down again if that fails
+func (c *Client) start(ctx context.Context, t transport) (*Client, error) {
+	c.transport = t
+	if _, err := c.Call(ctx, "hello", map[string]interface{}{"protocolVersion": ProtocolVersion}); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+	return c, nil
+}
+
+// Call sends any message type with its payload and returns the result.
+// A request the bridge rejects returns an *Error. If ctx has a deadline the
+// bridge gives up on the request then too, and if ctx is cancelled the
+// request is cancelled in the bridge.
+func (c *Client) Call(ctx context.Context, msgType string, payload map[string]interface{}) (map[string]interface{}, error) {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	id := "go_" + strconv.FormatUint(c.nextID.Add(1), 10)
+	resp, err := c.transport.call(ctx, id, msgType, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, &Error{Type: msgType, Message: resp.Error, Code: resp.Code, Field: resp.Field}
+	}
+	if resp.Result == nil {
+		resp.Result = map[string]interface{}{}
+	}
+	return resp.Result, nil
+}
+
+// OnCallback runs fn for each "callback" event carrying callbackID, until
+// the returned function is called. Callbacks run one at a time, in the order
+// the bridge raised them.
+func (c *Client) OnCallback(callbackID string, fn func(Event)) func() {
+	c.mu.Lock()
+	c.callbacks[callbackID] = fn
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.callbacks, callbackID)
+	}
+}
+
+// Subscribe delivers events of the given types, or all events if none are
+// given, until the returned function is called, which closes the channel.
+// A subscriber that falls behind has events dropped rather than stalling
+// the connection.
+func (c *Client) Subscribe(eventTypes ...string) (<-chan Event, func()) {
+	sub := &subscriber{
+		types: make(map[string]bool),
+		ch:    make(chan Event, eventBuffer),
+	}
+	for _, t := range eventTypes {
+		sub.types[t] = true
+	}
+
+	c.mu.Lock()
+	c.nextSub++
+	id := c.nextSub
+	c.subscribers[id] = sub
+	c.mu.Unlock()
+
+	return sub.ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, exists := c.subscribers[id]; exists {
+			delete(c.subscribers, id)
+			close(sub.ch)
+		}
+	}
+}
+
+// Done is closed when the connection to the bridge ends, for example when
+// the bridge quits after its last window closes
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err reports why the connection ended, once Done is closed
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close disconnects from the bridge. A bridge the client started is asked to
+// quit, and killed if it does not exit in time.
+func (c *Client) Close() error {
+	c.stopOnce.Do(func() {
+		if c.stop != nil {
+			c.stopErr = c.stop()
+		} else if c.transport != nil {
+			c.stopErr = c.transport.close()
+		}
+		c.closed(ErrClosed)
+	})
+	return c.stopErr
+}
+
+// closed records that the connection ended. Transports call it when their
+// connection fails or is closed.
+func (c *Client) closed(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		close(c.done)
+	})
+}
+
+// event queues an event from the transport for delivery
+func (c *Client) event(event Event) {
+	select {
+	case c.events <- event:
+	default:
+		log.Printf("[tsyne-client] Event queue full, dropping %s event", event.Type)
+	}
+}
+
+// deliverEvents runs callbacks and feeds subscribers on its own goroutine, so
+// a callback may make calls while the transport keeps reading
+func (c *Client) deliverEvents() {
+	for {
+		select {
+		case event := <-c.events:
+			c.deliver(event)
+		case <-c.done:
+			c.mu.Lock()
+			for id, sub := range c.subscribers {
+				delete(c.subscribers, id)
+				close(sub.ch)
+			}
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (c *Client) deliver(event Event) {
+	c.mu.Lock()
+	var callback func(Event)
+	if event.Type == "callback" {
+		callback = c.callbacks[event.CallbackID()]
+	}
+	for id, sub := range c.subscribers {
+		if len(sub.types) > 0 && !sub.types[event.Type] {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			log.Printf("[tsyne-client] Subscriber %d is full, dropping %s event", id, event.Type)
+		}
+	}
+	c.mu.Unlock()
+
+	if callback != nil {
+		callback(event)
+	}
+}
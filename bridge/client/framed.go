@@ -0,0 +1,241 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Frame format, as in the bridge's core/protocol.go:
+// [magic: 4 bytes][length: 4 bytes][crc32: 4 bytes][json: N bytes]
+var frameMagic = []byte{0xFF, 0xFE, 'T', 'S'}
+
+const (
+	frameHeaderSize = 12
+	maxFrameSize    = 10 * 1024 * 1024
+)
+
+// writeFrame writes one framed JSON message
+func writeFrame(w io.Writer, data []byte) error {
+	frame := make([]byte, frameHeaderSize+len(data))
+	copy(frame, frameMagic)
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(data)))
+	binary.BigEndian.PutUint32(frame[8:], crc32.ChecksumIEEE(data))
+	copy(frame[frameHeaderSize:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+// frameReader reads frames, skipping past anything that is not a valid one
+type frameReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// next returns the payload of the next valid frame
+func (fr *frameReader) next() ([]byte, error) {
+	for {
+		if data, ok := fr.take(); ok {
+			return data, nil
+		}
+		chunk := make([]byte, 64*1024)
+		n, err := fr.r.Read(chunk)
+		fr.buf = append(fr.buf, chunk[:n]...)
+		if err != nil && n == 0 {
+			return nil, err
+		}
+	}
+}
+
+// take removes one valid frame from the buffer, if a whole one is there
+func (fr *frameReader) take() ([]byte, bool) {
+	for {
+		at := bytes.Index(fr.buf, frameMagic)
+		if at < 0 {
+			// Keep a tail that may be the start of a marker split across reads
+			if keep := len(frameMagic) - 1; len(fr.buf) > keep {
+				fr.buf = fr.buf[len(fr.buf)-keep:]
+			}
+			return nil, false
+		}
+		fr.buf = fr.buf[at:]
+		if len(fr.buf) < frameHeaderSize {
+			return nil, false
+		}
+
+		length := binary.BigEndian.Uint32(fr.buf[4:8])
+		if length > maxFrameSize {
+			log.Printf("[tsyne-client] Skipping frame of %d bytes", length)
+			fr.buf = fr.buf[1:]
+			continue
+		}
+		size := frameHeaderSize + int(length)
+		if len(fr.buf) < size {
+			return nil, false
+		}
+		payload := fr.buf[frameHeaderSize:size]
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(fr.buf[8:12]) {
+			// The next frame may start inside the bad one
+			log.Printf("[tsyne-client] Checksum mismatch, resynchronising")
+			fr.buf = fr.buf[1:]
+			continue
+		}
+
+		data := append([]byte(nil), payload...)
+		fr.buf = fr.buf[size:]
+		return data, true
+	}
+}
+
+// framedMessage is anything the bridge writes: a response has an id, an
+// event has a type and no id
+type framedMessage struct {
+	ID *string `json:"id"`
+	response
+	Event
+}
+
+// framedTransport speaks the bridge's framed protocol, over a spawned
+// bridge's stdin and stdout or over a socket connection
+type framedTransport struct {
+	client *Client
+	closer io.Closer
+
+	writeMu sync.Mutex
+	w       io.Writer
+
+	mu      sync.Mutex
+	pending map[string]chan response
+	ready   chan struct{}
+	done    chan struct{}
+}
+
+func newFramedTransport(c *Client, r io.Reader, w io.Writer, closer io.Closer) *framedTransport {
+	t := &framedTransport{
+		client:  c,
+		closer:  closer,
+		w:       w,
+		pending: make(map[string]chan response),
+		ready:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go t.readLoop(r)
+	return t
+}
+
+// waitReady waits for the bridge's ready signal, which it sends before
+// reading any message
+func (t *framedTransport) waitReady(ctx context.Context) error {
+	select {
+	case <-t.ready:
+		return nil
+	case <-t.done:
+		return t.client.err
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for bridge: %w", ctx.Err())
+	}
+}
+
+func (t *framedTransport) readLoop(r io.Reader) {
+	reader := &frameReader{r: r}
+	var readyOnce sync.Once
+	for {
+		data, err := reader.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+				errors.Is(err, net.ErrClosed) || errors.Is(err, os.ErrClosed) {
+				err = ErrClosed
+			}
+			t.client.closed(err)
+			close(t.done)
+			return
+		}
+
+		var msg framedMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Printf("[tsyne-client] Error parsing bridge message: %v", err)
+			continue
+		}
+		if msg.ID == nil {
+			t.client.event(msg.Event)
+			continue
+		}
+
+		msg.response.ID = *msg.ID
+		if msg.response.ID == "ready" {
+			readyOnce.Do(func() { close(t.ready) })
+			continue
+		}
+		t.mu.Lock()
+		waiter, exists := t.pending[msg.response.ID]
+		delete(t.pending, msg.response.ID)
+		t.mu.Unlock()
+		if exists {
+			waiter <- msg.response
+		}
+	}
+}
+
+func (t *framedTransport) send(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	return writeFrame(t.w, data)
+}
+
+func (t *framedTransport) call(ctx context.Context, id, msgType string, payload map[string]interface{}) (response, error) {
+	waiter := make(chan response, 1)
+	t.mu.Lock()
+	t.pending[id] = waiter
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	msg := map[string]interface{}{"id": id, "type": msgType, "payload": payload}
+	if deadline, ok := ctx.Deadline(); ok {
+		// The bridge stops waiting at the same time, freeing the next message
+		msg["deadlineMs"] = max(time.Until(deadline).Milliseconds(), 1)
+	}
+	if err := t.send(msg); err != nil {
+		if t.client.Err() != nil {
+			return response{}, ErrClosed
+		}
+		return response{}, fmt.Errorf("sending %s: %w", msgType, err)
+	}
+
+	select {
+	case resp := <-waiter:
+		return resp, nil
+	case <-t.done:
+		return response{}, ErrClosed
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.send(map[string]interface{}{
+				"id":      id + "_cancel",
+				"type":    "cancel",
+				"payload": map[string]interface{}{"requestId": id},
+			})
+		}
+		return response{}, ctx.Err()
+	}
+}
+
+func (t *framedTransport) close() error {
+	return t.closer.Close()
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/paul-hammant/tsyne/bridge/proto"
+)
+
+// subscribedID answers the command that confirms the event subscription
+const subscribedID = "go_subscribed"
+
+// tokenCredentials sends the bridge's token with every gRPC call
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// sessionCredentials names the bridge session every gRPC call is for
+type sessionCredentials string
+
+func (s sessionCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"session": string(s)}, nil
+}
+
+func (s sessionCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// WithSession is a DialGrpc option that attaches to a session opened with
+// the bridge's OpenSession RPC, rather than the default session. The client
+// then sees only that session's widgets, windows and events.
+func WithSession(id string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(sessionCredentials(id))
+}
+
+// grpcTransport sends each call as a unary Invoke, so calls run concurrently
+// and carry their own deadline and cancellation. Events arrive on a Session
+// stream kept open alongside.
+type grpcTransport struct {
+	client  *Client
+	conn    *grpc.ClientConn
+	service pb.BridgeServiceClient
+	cancel  context.CancelFunc
+}
+
+// dialGrpc connects to a bridge's gRPC service and subscribes to its events
+func dialGrpc(ctx context.Context, c *Client, address, token string, opts ...grpc.DialOption) (*grpcTransport, error) {
+	// Later options win, so callers may replace the insecure default
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(tokenCredentials(token)),
+	}, opts...)
+	conn, err := grpc.NewClient(address, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	t := &grpcTransport{
+		client:  c,
+		conn:    conn,
+		service: pb.NewBridgeServiceClient(conn),
+		cancel:  cancel,
+	}
+	if err := t.subscribe(ctx, streamCtx); err != nil {
+		t.close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// subscribe opens the Session stream and waits until it is subscribed.
+// The session handles a subscription before any command sent after it, so
+// once a ping sent after the subscription is answered, no event can be missed.
+func (t *grpcTransport) subscribe(ctx, streamCtx context.Context) error {
+	stream, err := t.service.Session(streamCtx)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&pb.SessionRequest{Kind: &pb.SessionRequest_Subscribe{Subscribe: &pb.EventSubscription{}}})
+	if err == nil {
+		err = stream.Send(&pb.SessionRequest{Kind: &pb.SessionRequest_Command{
+			Command: &pb.InvokeRequest{Id: subscribedID, Type: "ping"},
+		}})
+	}
+	if err != nil {
+		return fmt.Errorf("subscribing to events: %w", err)
+	}
+
+	subscribed := make(chan error, 1)
+	go t.readEvents(stream, subscribed)
+	select {
+	case err := <-subscribed:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("subscribing to events: %w", ctx.Err())
+	}
+}
+
+// readEvents hands session events to the client until the stream ends
+func (t *grpcTransport) readEvents(stream pb.BridgeService_SessionClient, subscribed chan<- error) {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+				err = ErrClosed
+			}
+			select {
+			case subscribed <- err:
+			default:
+			}
+			t.client.closed(err)
+			return
+		}
+
+		switch kind := msg.Kind.(type) {
+		case *pb.SessionResponse_Response:
+			if kind.Response.GetId() == subscribedID {
+				subscribed <- nil
+			}
+		case *pb.SessionResponse_Event:
+			t.client.event(fromProtoEvent(kind.Event))
+		}
+	}
+}
+
+// fromProtoEvent converts a gRPC event. gRPC carries event data as strings,
+// so a boolean arrives as "true" and a number in its decimal form.
+func fromProtoEvent(event *pb.Event) Event {
+	data := make(map[string]interface{}, len(event.GetData()))
+	for key, value := range event.GetData() {
+		data[key] = value
+	}
+	return Event{Type: event.GetType(), WidgetID: event.GetWidgetId(), Data: data}
+}
+
+func (t *grpcTransport) call(ctx context.Context, id, msgType string, payload map[string]interface{}) (response, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return response{}, err
+	}
+	resp, err := t.service.Invoke(ctx, &pb.InvokeRequest{Id: id, Type: msgType, Payload: string(encoded)})
+	if err != nil {
+		if ctx.Err() != nil {
+			return response{}, ctx.Err()
+		}
+		if t.client.Err() != nil {
+			return response{}, ErrClosed
+		}
+		return response{}, fmt.Errorf("%s: %w", msgType, err)
+	}
+
+	result := response{
+		ID:      resp.GetId(),
+		Success: resp.GetSuccess(),
+		Error:   resp.GetError(),
+		Code:    resp.GetCode(),
+		Field:   resp.GetField(),
+	}
+	if resp.GetResult() != "" {
+		if err := json.Unmarshal([]byte(resp.GetResult()), &result.Result); err != nil {
+			return response{}, fmt.Errorf("%s: decoding result: %w", msgType, err)
+		}
+	}
+	return result, nil
+}
+
+func (t *grpcTransport) close() error {
+	t.cancel()
+	return t.conn.Close()
+}
@@ -0,0 +1,229 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// quitTimeout is how long Close waits for a spawned bridge to quit before
+// killing it
+const quitTimeout = 5 * time.Second
+
+// Options say how to start a bridge process
+type Options struct {
+	// BridgePath is the tsyne-bridge executable. If empty, $TSYNE_BRIDGE is
+	// used, then tsyne-bridge from the PATH.
+	BridgePath string
+	// Headless runs the bridge in test mode, without a window system
+	Headless bool
+	// Args are extra bridge flags, e.g. "--log-level=debug"
+	Args []string
+	// Stderr receives the bridge's log output; os.Stderr if nil
+	Stderr io.Writer
+}
+
+func (o Options) command(modeArgs ...string) (*exec.Cmd, error) {
+	path := o.BridgePath
+	if path == "" {
+		path = os.Getenv("TSYNE_BRIDGE")
+	}
+	if path == "" {
+		found, err := exec.LookPath("tsyne-bridge")
+		if err != nil {
+			return nil, fmt.Errorf("tsyne-bridge not found; set Options.BridgePath or $TSYNE_BRIDGE: %w", err)
+		}
+		path = found
+	}
+
+	args := append([]string{}, modeArgs...)
+	if o.Headless {
+		args = append(args, "--headless")
+	}
+	args = append(args, o.Args...)
+
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = o.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	return cmd, nil
+}
+
+// StartStdio starts a bridge process and talks to it over its stdin and
+// stdout, as the TypeScript client does. ctx bounds the startup only.
+func StartStdio(ctx context.Context, opts Options) (*Client, error) {
+	cmd, err := opts.command()
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting bridge: %w", err)
+	}
+
+	c := newClient()
+	t := newFramedTransport(c, stdout, stdin, stdin)
+	c.stop = func() error {
+		// Closing stdin alone would leave a windowed bridge running for the
+		// watchdog's grace period
+		quitCtx, cancel := context.WithTimeout(context.Background(), quitTimeout)
+		defer cancel()
+		c.Call(quitCtx, "quit", nil)
+		t.close()
+		return waitOrKill(cmd)
+	}
+	if err := t.waitReady(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c.start(ctx, t)
+}
+
+// StartGrpc starts a bridge process in gRPC mode and connects to the port it
+// announces. ctx bounds the startup only.
+func StartGrpc(ctx context.Context, opts Options) (*Client, error) {
+	cmd, err := opts.command("--mode=grpc")
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting bridge: %w", err)
+	}
+	stop := func() error {
+		// The bridge shuts down on this line, or when stdin closes
+		io.WriteString(stdin, "shutdown\n")
+		stdin.Close()
+		return waitOrKill(cmd)
+	}
+
+	// The bridge prints one line with its port and token
+	type connectionInfo struct {
+		Port  int    `json:"grpcPort"`
+		Token string `json:"token"`
+	}
+	infoCh := make(chan connectionInfo, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		line, err := bufio.NewReader(stdout).ReadString('\n')
+		if err != nil {
+			errCh <- fmt.Errorf("reading connection info: %w", err)
+			return
+		}
+		var info connectionInfo
+		if err := json.Unmarshal([]byte(line), &info); err != nil || info.Port == 0 {
+			errCh <- fmt.Errorf("invalid connection info from bridge: %q", strings.TrimSpace(line))
+			return
+		}
+		infoCh <- info
+		// Nothing else is written, but keep the pipe drained
+		io.Copy(io.Discard, stdout)
+	}()
+
+	var info connectionInfo
+	select {
+	case info = <-infoCh:
+	case err := <-errCh:
+		stop()
+		return nil, err
+	case <-ctx.Done():
+		stop()
+		return nil, fmt.Errorf("waiting for bridge: %w", ctx.Err())
+	}
+
+	c := newClient()
+	t, err := dialGrpc(ctx, c, fmt.Sprintf("localhost:%d", info.Port), info.Token)
+	if err != nil {
+		c.closed(err)
+		stop()
+		return nil, err
+	}
+	c.stop = func() error {
+		t.close()
+		return stop()
+	}
+	return c.start(ctx, t)
+}
+
+// DialSocket attaches to a bridge running in socket mode, at unix:/path or
+// tcp:host:port as given to its --listen flag, with the token it announced.
+// The bridge serves one client at a time.
+func DialSocket(ctx context.Context, address, token string) (*Client, error) {
+	network, addr, found := strings.Cut(address, ":")
+	if !found || addr == "" {
+		return nil, fmt.Errorf("invalid socket address %q, expected unix:/path or tcp:host:port", address)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newClient()
+	t := newFramedTransport(c, conn, conn, conn)
+	// The bridge reads nothing else, and sends no ready message, until the
+	// token has matched
+	resp, err := t.call(ctx, "go_auth", "auth", map[string]interface{}{"token": token})
+	if err == nil && !resp.Success {
+		err = &Error{Type: "auth", Message: resp.Error, Code: resp.Code}
+	}
+	if err == nil {
+		err = t.waitReady(ctx)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c.start(ctx, t)
+}
+
+// DialGrpc attaches to a bridge running in gRPC mode, given its address and
+// the token it announced. Pass grpc.WithTransportCredentials to reach a
+// bridge started with TLS.
+func DialGrpc(ctx context.Context, address, token string, opts ...grpc.DialOption) (*Client, error) {
+	c := newClient()
+	t, err := dialGrpc(ctx, c, address, token, opts...)
+	if err != nil {
+		c.closed(err)
+		return nil, err
+	}
+	return c.start(ctx, t)
+}
+
+// waitOrKill waits for a bridge process to exit, killing it if it takes
+// longer than quitTimeout
+func waitOrKill(cmd *exec.Cmd) error {
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(quitTimeout):
+		cmd.Process.Kill()
+		return fmt.Errorf("bridge did not exit within %v and was killed", quitTimeout)
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+)
+
+// Typed helpers for the most common messages. Anything else can be sent
+// with Call, using the message types listed by the bridge's describe message.
+
+// Window describes a window to create
+type Window struct {
+	ID        string
+	Title     string
+	Width     int // 0 leaves the size to Fyne
+	Height    int
+	FixedSize bool
+}
+
+// CreateWindow creates a window; show it with ShowWindow once it has content
+func (c *Client) CreateWindow(ctx context.Context, w Window) error {
+	payload := map[string]interface{}{"id": w.ID, "title": w.Title}
+	if w.Width > 0 && w.Height > 0 {
+		payload["width"] = w.Width
+		payload["height"] = w.Height
+	}
+	if w.FixedSize {
+		payload["fixedSize"] = true
+	}
+	_, err := c.Call(ctx, "createWindow", payload)
+	return err
+}
+
+// ShowWindow shows a window
+func (c *Client) ShowWindow(ctx context.Context, windowID string) error {
+	_, err := c.Call(ctx, "showWindow", map[string]interface{}{"windowId": windowID})
+	return err
+}
+
+// SetContent makes a widget the content of a window
+func (c *Client) SetContent(ctx context.Context, windowID, widgetID string) error {
+	_, err := c.Call(ctx, "setContent", map[string]interface{}{"windowId": windowID, "widgetId": widgetID})
+	return err
+}
+
+// CreateLabel creates a label
+func (c *Client) CreateLabel(ctx context.Context, id, text string) error {
+	_, err := c.Call(ctx, "createLabel", map[string]interface{}{"id": id, "text": text})
+	return err
+}
+
+// CreateButton creates a button. onTap, if not nil, runs each time the
+// button is tapped.
+func (c *Client) CreateButton(ctx context.Context, id, text string, onTap func()) error {
+	payload := map[string]interface{}{"id": id, "text": text}
+	if onTap == nil {
+		_, err := c.Call(ctx, "createButton", payload)
+		return err
+	}
+
+	callbackID := id + "_tapped"
+	payload["callbackId"] = callbackID
+	return c.createWithCallback(ctx, "createButton", payload, callbackID, func(Event) { onTap() })
+}
+
+// CreateEntry creates a text entry. onSubmit, if not nil, runs with the
+// entry's text each time Enter is pressed in it.
+func (c *Client) CreateEntry(ctx context.Context, id, placeholder string, onSubmit func(text string)) error {
+	payload := map[string]interface{}{"id": id, "placeholder": placeholder}
+	if onSubmit == nil {
+		_, err := c.Call(ctx, "createEntry", payload)
+		return err
+	}
+
+	callbackID := id + "_submitted"
+	payload["callbackId"] = callbackID
+	return c.createWithCallback(ctx, "createEntry", payload, callbackID, func(event Event) {
+		text, _ := event.Data["text"].(string)
+		onSubmit(text)
+	})
+}
+
+// createWithCallback registers a callback before creating the widget that
+// raises it, so the first event cannot be missed
+func (c *Client) createWithCallback(ctx context.Context, msgType string, payload map[string]interface{}, callbackID string, fn func(Event)) error {
+	remove := c.OnCallback(callbackID, fn)
+	if _, err := c.Call(ctx, msgType, payload); err != nil {
+		remove()
+		return err
+	}
+	return nil
+}
+
+// CreateVBox creates a vertical box holding the given widgets
+func (c *Client) CreateVBox(ctx context.Context, id string, children ...string) error {
+	_, err := c.Call(ctx, "createVBox", map[string]interface{}{"id": id, "children": children})
+	return err
+}
+
+// CreateHBox creates a horizontal box holding the given widgets
+func (c *Client) CreateHBox(ctx context.Context, id string, children ...string) error {
+	_, err := c.Call(ctx, "createHBox", map[string]interface{}{"id": id, "children": children})
+	return err
+}
+
+// SetText sets the text of a label or entry
+func (c *Client) SetText(ctx context.Context, widgetID, text string) error {
+	_, err := c.Call(ctx, "setText", map[string]interface{}{"widgetId": widgetID, "text": text})
+	return err
+}
+
+// GetText returns the text of a label, entry, button or check
+func (c *Client) GetText(ctx context.Context, widgetID string) (string, error) {
+	result, err := c.Call(ctx, "getText", map[string]interface{}{"widgetId": widgetID})
+	if err != nil {
+		return "", err
+	}
+	text, _ := result["text"].(string)
+	return text, nil
+}
+
+// ClickWidget taps a widget as a user would, for tests
+func (c *Client) ClickWidget(ctx context.Context, widgetID string) error {
+	_, err := c.Call(ctx, "clickWidget", map[string]interface{}{"widgetId": widgetID})
+	return err
+}
+
+// TypeText types text into an entry as a user would, for tests
+func (c *Client) TypeText(ctx context.Context, widgetID, text string) error {
+	_, err := c.Call(ctx, "typeText", map[string]interface{}{"widgetId": widgetID, "text": text})
+	return err
+}
+
+// Quit asks the bridge to quit its Fyne app
+func (c *Client) Quit(ctx context.Context) error {
+	_, err := c.Call(ctx, "quit", nil)
+	return err
+}
@@ -0,0 +1,295 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"runtime/debug"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// runOnMain runs fn on the Fyne main thread and waits for it.
+// Inside a batch the steps already run on the main thread, so fn runs inline;
+// calling fyne.DoAndWait from the main thread would not wait.
+// A panic in fn is raised again in the caller, where the handler's recovery
+// can report it, instead of crashing the main thread.
+// ctx is the request's context. Once the request has been cancelled or has
+// timed out, fn is skipped and the handler is unwound, so a handler that was
+// given up on makes no further UI changes.
+func (b *Bridge) runOnMain(ctx context.Context, fn func()) {
+	if requestAbandoned(ctx) {
+		panic(abandonedHandler{})
+	}
+	if b.inBatch {
+		fn()
+		return
+	}
+
+	var panicked *mainThreadPanic
+	fyne.DoAndWait(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = &mainThreadPanic{value: r, stack: debug.Stack()}
+			}
+		}()
+		fn()
+	})
+	if panicked != nil {
+		panic(*panicked)
+	}
+}
+
+// batchSnapshot records the bridge state a failed batch is rolled back to
+type batchSnapshot struct {
+	widgets        map[string]fyne.CanvasObject
+	widgetMeta     map[string]WidgetMetadata
+	callbacks      map[string]string
+	contextMenus   map[string]*fyne.Menu
+	tableData      map[string][][]string
+	listData       map[string][]string
+	toolbarItems   map[string]*ToolbarItemsMetadata
+	toolbarActions map[string]*widget.ToolbarAction
+	childToParent  map[string]string
+	customIds      map[string]string
+	resources      map[string][]byte
+	windows        map[string]fyne.Window
+	windowContent  map[string]string
+	contentObject  map[string]fyne.CanvasObject // window ID -> content shown before the batch
+
+	containerObjects map[*fyne.Container][]fyne.CanvasObject // children before the batch
+}
+
+// handleBatch executes an ordered list of messages as one unit.
+// All steps run inside a single fyne.DoAndWait, so the UI never renders a
+// half-built tree, and no other message is dispatched until the batch ends.
+// If a step fails, widgets and windows created by earlier steps are removed,
+// and container children and window content are put back as they were, along
+// with callbacks, toolbar items and registered resources.
+// Steps that would block the main thread, such as updateImage fetching a url,
+// are refused before any step runs.
+func (b *Bridge) handleBatch(msg Message) {
+	rawSteps, ok := msg.Payload["messages"].([]interface{})
+	if !ok {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "Batch requires a messages array",
+		})
+		return
+	}
+
+	steps := make([]Message, 0, len(rawSteps))
+	for i, raw := range rawSteps {
+		step, err := parseBatchStep(raw)
+		if err != nil {
+			b.sendResponse(Response{
+				ID:      msg.ID,
+				Success: false,
+				Error:   fmt.Sprintf("Invalid batch step %d: %v", i, err),
+			})
+			return
+		}
+		steps = append(steps, step)
+	}
+
+	b.dispatchMu.Lock()
+	defer b.dispatchMu.Unlock()
+
+	results := make([]interface{}, 0, len(steps))
+	var failure string
+	var failedStep Response
+
+	fyne.DoAndWait(func() {
+		b.inBatch = true
+		defer func() { b.inBatch = false }()
+
+		snapshot := b.takeBatchSnapshot()
+
+		for i, step := range steps {
+			resp := b.runBatchStep(step)
+			results = append(results, batchResult(resp))
+			if !resp.Success {
+				failure = fmt.Sprintf("Batch step %d (%s) failed: %s", i, step.Type, resp.Error)
+				failedStep = resp
+				break
+			}
+		}
+
+		if failure != "" {
+			b.rollbackBatch(snapshot)
+		}
+	})
+
+	if failure != "" {
+		logProtocol.Warnf("%s, rolled back", failure)
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   failure,
+			Code:    failedStep.Code,
+			Field:   failedStep.Field,
+			Result: map[string]interface{}{
+				"results":    results,
+				"rolledBack": true,
+			},
+		})
+		return
+	}
+
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result:  map[string]interface{}{"results": results},
+	})
+}
+
+// parseBatchStep converts one JSON step into a Message
+func parseBatchStep(raw interface{}) (Message, error) {
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return Message{}, fmt.Errorf("step must be an object")
+	}
+
+	msgType, _ := fields["type"].(string)
+	if msgType == "" {
+		return Message{}, fmt.Errorf("step has no type")
+	}
+	if msgType == "batch" {
+		return Message{}, fmt.Errorf("nested batches are not supported")
+	}
+
+	id, _ := fields["id"].(string)
+	payload, _ := fields["payload"].(map[string]interface{})
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+
+	// Steps run on the main thread, where waiting on the network would freeze the UI
+	if _, hasURL := payload["url"]; hasURL && msgType == "updateImage" {
+		return Message{}, fmt.Errorf("updateImage with a url fetches over the network and cannot be batched")
+	}
+
+	return Message{ID: id, Type: msgType, Payload: payload}, nil
+}
+
+// runBatchStep dispatches one step and collects its response.
+// A step must respond before its handler returns; anything that answers
+// later (dialogs, for instance) cannot take part in a batch.
+func (b *Bridge) runBatchStep(step Message) Response {
+	callerID := step.ID
+	step.ID = b.routes.newID("batch")
+
+	collector := newChannelResponder(1, 0)
+	defer b.routes.remove(step.ID)
+	b.routes.add(step.ID, collector)
+	b.dispatchMessage(step)
+
+	var resp Response
+	select {
+	case resp = <-collector.responses:
+	default:
+		resp = Response{
+			Success: false,
+			Error:   fmt.Sprintf("%s did not respond synchronously and cannot be batched", step.Type),
+		}
+	}
+
+	resp.ID = callerID
+	return resp
+}
+
+// batchResult is the JSON form of a step's response in the batch result
+func batchResult(resp Response) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":      resp.ID,
+		"success": resp.Success,
+	}
+	if resp.Result != nil {
+		result["result"] = resp.Result
+	}
+	if resp.Error != "" {
+		result["error"] = resp.Error
+	}
+	if resp.Code != "" {
+		result["code"] = resp.Code
+	}
+	if resp.Field != "" {
+		result["field"] = resp.Field
+	}
+	return result
+}
+
+// takeBatchSnapshot copies the widget, window and resource bookkeeping.
+// Must run on the main thread so window content can be read.
+func (b *Bridge) takeBatchSnapshot() *batchSnapshot {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snapshot := &batchSnapshot{
+		widgets:        maps.Clone(b.widgets),
+		widgetMeta:     maps.Clone(b.widgetMeta),
+		callbacks:      maps.Clone(b.callbacks),
+		contextMenus:   maps.Clone(b.contextMenus),
+		tableData:      maps.Clone(b.tableData),
+		listData:       maps.Clone(b.listData),
+		toolbarItems:   maps.Clone(b.toolbarItems),
+		toolbarActions: maps.Clone(b.toolbarActions),
+		childToParent:  maps.Clone(b.childToParent),
+		customIds:      maps.Clone(b.customIds),
+		resources:      maps.Clone(b.resources),
+		windows:        maps.Clone(b.windows),
+		windowContent:  maps.Clone(b.windowContent),
+		contentObject:  make(map[string]fyne.CanvasObject, len(b.windows)),
+
+		containerObjects: make(map[*fyne.Container][]fyne.CanvasObject),
+	}
+	for _, obj := range b.widgets {
+		if cont, ok := obj.(*fyne.Container); ok {
+			snapshot.containerObjects[cont] = append([]fyne.CanvasObject(nil), cont.Objects...)
+		}
+	}
+	for windowID, win := range b.windows {
+		snapshot.contentObject[windowID] = win.Content()
+	}
+	return snapshot
+}
+
+// rollbackBatch undoes the steps of a failed batch.
+// Must run on the main thread.
+func (b *Bridge) rollbackBatch(snapshot *batchSnapshot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Put back the children of containers that existed before the batch
+	for cont, objects := range snapshot.containerObjects {
+		cont.Objects = objects
+		cont.Refresh()
+	}
+
+	// Close windows created by the batch and restore the content of the others
+	for windowID, win := range b.windows {
+		if _, existed := snapshot.windows[windowID]; !existed {
+			win.Close()
+			continue
+		}
+		if previous := snapshot.contentObject[windowID]; previous != nil && win.Content() != previous {
+			win.SetContent(previous)
+		}
+	}
+
+	b.widgets = snapshot.widgets
+	b.widgetMeta = snapshot.widgetMeta
+	b.callbacks = snapshot.callbacks
+	b.contextMenus = snapshot.contextMenus
+	b.tableData = snapshot.tableData
+	b.listData = snapshot.listData
+	b.toolbarItems = snapshot.toolbarItems
+	b.toolbarActions = snapshot.toolbarActions
+	b.childToParent = snapshot.childToParent
+	b.customIds = snapshot.customIds
+	b.resources = snapshot.resources
+	b.windows = snapshot.windows
+	b.windowContent = snapshot.windowContent
+}
@@ -0,0 +1,355 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// handleMessage dispatches a message to its handler.
+// A batch takes the dispatch lock exclusively so no other message interleaves with it.
+func (b *Bridge) handleMessage(msg Message) {
+	b.recorder.message(msg)
+	b.history.add(msg)
+	b.watchdog.seen()
+
+	request := b.startRequest(msg)
+	msg = request.msg
+
+	if msg.Type == "cancel" {
+		// The request being cancelled may be holding up the dispatch lock
+		defer b.finishRequest(request)
+		b.dispatchMessage(msg)
+		return
+	}
+
+	// A cancelled or timed-out request is answered straight away, and the
+	// next message need not wait for its handler, which carries on alone.
+	// Giving up on a request does not stop its handler, but the handler no
+	// longer holds up a batch, and runOnMain unwinds it before it touches the
+	// UI again. Work it has already queued on the main thread still runs.
+	handled := make(chan struct{})
+	go func() {
+		defer b.reportFatalPanic()
+		defer close(handled)
+		defer b.finishRequest(request)
+
+		if msg.Type == "batch" {
+			b.handleBatch(msg)
+			return
+		}
+		b.dispatchMu.RLock()
+		unlock := sync.OnceFunc(b.dispatchMu.RUnlock)
+		defer unlock()
+		stop := context.AfterFunc(msg.Context(), unlock)
+		defer stop()
+		b.dispatchMessage(msg)
+	}()
+	select {
+	case <-handled:
+	case <-msg.Context().Done():
+		b.abandonRequest(request)
+	}
+}
+
+// dispatchMessage runs the registered handler for a message type
+func (b *Bridge) dispatchMessage(msg Message) {
+	spec, exists := handlers.lookup(msg.Type)
+	if !exists {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Unknown message type: %s", msg.Type),
+			Code:    codeUnknownMessageType,
+		})
+		return
+	}
+
+	if spec.TestOnly && !b.testMode {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("%s is only supported in test mode", msg.Type),
+			Code:    codeTestModeOnly,
+		})
+		return
+	}
+
+	if err := b.validatePayload(spec.Payload, msg.Payload); err != nil {
+		b.sendResponse(err.response(msg.ID))
+		return
+	}
+
+	defer b.recoverMessage(msg)
+	spec.Handler(b, msg)
+}
+
+// Helper functions for gRPC mode
+
+// generateSecureToken generates a random secure token
+func generateSecureToken(length int) string {
+	bytes := make([]byte, length)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// RunGrpc runs the bridge in gRPC mode. It returns an error if the bridge
+// could not start.
+func RunGrpc(testMode bool, opts Options) error {
+	logBridge.Infof("Starting in gRPC mode (testMode: %v)", testMode)
+	// 1. Use the given token or generate a secure one
+	token := opts.Grpc.Token
+	if token == "" {
+		token = generateSecureToken(32)
+	}
+
+	// 2. Create bridge
+	// Responses are returned per call and events flow through SubscribeEvents,
+	// so nothing is written to stdout beyond the connection info line
+	bridge := NewBridge(testMode)
+	if err := bridge.ApplyOptions(opts); err != nil {
+		return err
+	}
+	defer bridge.reportFatalPanic()
+
+	// 3. Bind the listener, so the port is ours before anyone is told of it
+	server, err := listenGrpc(opts.Grpc, token, bridge)
+	if err != nil {
+		return fmt.Errorf("failed to start gRPC on %s: %w", opts.Grpc.Address, err)
+	}
+	defer server.stop()
+
+	// 4. Serve in the background; connections made before Serve starts wait in the backlog
+	go func() {
+		if err := server.serve(); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	// 5. Send connection info to TypeScript via stdout
+	initMsg := map[string]interface{}{
+		"grpcPort":    server.port(),
+		"grpcAddress": server.listener.Addr().String(),
+		"tls":         opts.Grpc.tls(),
+		"token":       token,
+		"protocol":    "grpc",
+	}
+	for key, value := range bridge.capabilities() {
+		initMsg[key] = value
+	}
+	if bridge.websocketInfo != nil {
+		initMsg["websocket"] = bridge.websocketInfo
+	}
+	jsonData, _ := json.Marshal(initMsg)
+	os.Stdout.Write(jsonData)
+	os.Stdout.Write([]byte("\n"))
+	os.Stdout.Sync()
+
+	logGrpc.Infof("Sent connection info: port=%d", server.port())
+
+	// 6. Keep stdin open for shutdown signal
+	shutdownChan := make(chan bool)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "shutdown" {
+				shutdownChan <- true
+				return
+			}
+		}
+		// The launcher can no longer ask us to shut down
+		bridge.watchdog.inputClosed()
+	}()
+
+	// 7. Run the Fyne app
+	if !testMode {
+		go func() {
+			defer bridge.reportFatalPanic()
+			bridge.app.Run()
+		}()
+	}
+
+	<-shutdownChan
+	logGrpc.Infof("Bridge shutting down...")
+	return nil
+}
+
+// framedQueueSize is how many framed messages may be read ahead of the one being handled
+const framedQueueSize = 64
+
+// serveFramed reads framed messages from r and handles them until r is closed.
+// Responses go to the bridge's current Responder.
+func (b *Bridge) serveFramed(r io.Reader) {
+	b.serveFrames(newFrameReader(r))
+}
+
+// serveFrames handles the frames read by reader until its input is closed
+func (b *Bridge) serveFrames(reader *frameReader) {
+	// Messages are handled in order by one goroutine while this one keeps
+	// reading, so a cancel can reach a request that is still running
+	queue := make(chan Message, framedQueueSize)
+	drained := make(chan struct{})
+	go func() {
+		defer b.reportFatalPanic()
+		defer close(drained)
+		for msg := range queue {
+			b.handleMessage(msg)
+		}
+	}()
+	defer func() {
+		close(queue)
+		<-drained
+	}()
+
+	// IPC Safeguard #3 & #4: Read framed messages with length-prefix and CRC32 validation
+	for {
+		// Read framed message, resynchronising past corrupted input
+		jsonData, loss, err := reader.ReadFrame()
+		if loss != nil {
+			b.sendProtocolError(loss.Reason, loss.BytesSkipped, loss.LostIDs)
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logProtocol.Errorf("Error reading framed message: %v", err)
+			}
+			// Stream closed (or failed) - nothing more can be read
+			return
+		}
+
+		// Parse JSON or CBOR message
+		var msg Message
+		if err := decodeMessage(jsonData, &msg); err != nil {
+			logProtocol.Warnf("Error parsing message: %v", err)
+			b.sendProtocolError(fmt.Sprintf("invalid message: %v", err), len(jsonData), findMessageIDs(jsonData))
+			continue
+		}
+
+		// Handle the message
+		if msg.Type == "cancel" {
+			b.handleMessage(msg)
+			continue
+		}
+		queue <- msg
+	}
+}
+
+// sendReady tells a framed-protocol client the bridge is ready to receive commands
+func (b *Bridge) sendReady() {
+	ready := b.capabilities()
+	ready["status"] = "ready"
+	if b.websocketInfo != nil {
+		ready["websocket"] = b.websocketInfo
+	}
+	b.sendResponse(Response{
+		ID:      "ready",
+		Success: true,
+		Result:  ready,
+	})
+}
+
+// Options are the settings shared by every mode, set from the command line
+type Options struct {
+	WebSocket    WebSocketOptions
+	Grpc         GrpcOptions
+	Socket       SocketOptions
+	RecordPath   string // --record file, empty to disable
+	CrashDir     string // directory for crash reports, temp dir if empty
+	Watchdog     WatchdogOptions
+	EventQueue   int // outbound queue size
+	EventMaxRate int // coalesced events per second per callback, 0 for no limit
+}
+
+// DefaultOptions returns the options the command line defaults to
+func DefaultOptions() Options {
+	return Options{
+		Grpc:         GrpcOptions{Address: defaultGrpcAddress},
+		Watchdog:     WatchdogOptions{Enabled: true, Grace: 5 * time.Second},
+		EventQueue:   defaultOutboundQueueSize,
+		EventMaxRate: defaultEventMaxRate,
+	}
+}
+
+// ApplyOptions starts the optional listeners and recording for a new bridge.
+// It returns an error if the record file or the WebSocket listener cannot be
+// opened; the bridge is then left without either.
+func (b *Bridge) ApplyOptions(opts Options) error {
+	b.crashDir = opts.CrashDir
+	if opts.EventQueue > 0 {
+		b.outbound.setLimit(opts.EventQueue)
+	}
+	b.throttle.setMaxRate(opts.EventMaxRate)
+	if opts.RecordPath != "" {
+		rec, err := openRecorder(opts.RecordPath)
+		if err != nil {
+			return fmt.Errorf("failed to open record file %s: %w", opts.RecordPath, err)
+		}
+		b.recorder = rec
+		logBridge.Infof("Recording session to %s", opts.RecordPath)
+	}
+	if err := b.startWebSocket(opts.WebSocket); err != nil {
+		b.recorder.close()
+		b.recorder = nil
+		return err
+	}
+	// With only the heartbeat to check, and no timeout, there is nothing to watch
+	if opts.Watchdog.Enabled && (!opts.Watchdog.HeartbeatOnly || opts.Watchdog.HeartbeatTimeout > 0) {
+		b.watchdog = newWatchdog(b, opts.Watchdog)
+		b.watchdog.start()
+	}
+	return nil
+}
+
+// RunStdio runs the bridge in stdio mode, the default. It returns an error if
+// the bridge could not start.
+func RunStdio(testMode bool, opts Options) error {
+	logBridge.Infof("Starting in stdio mode (testMode: %v)", testMode)
+	bridge := NewBridge(testMode)
+	bridge.SetResponder(newFramedResponder(os.Stdout))
+	if err := bridge.ApplyOptions(opts); err != nil {
+		return err
+	}
+	defer bridge.reportFatalPanic()
+
+	// Read messages from stdin in a goroutine
+	go func() {
+		defer bridge.reportFatalPanic()
+		bridge.serveFramed(os.Stdin)
+
+		// If stdin closes, signal quit
+		if testMode {
+			select {
+			case bridge.quitChan <- true:
+			default:
+			}
+		} else {
+			bridge.watchdog.inputClosed()
+		}
+	}()
+
+	// Send ready signal to indicate bridge is ready to receive commands
+	bridge.sendReady()
+
+	// Run the Fyne app
+	// In normal mode, this blocks until quit
+	// In test mode, DON'T call app.Run() - test apps don't need the event loop
+	if !testMode {
+		bridge.app.Run()
+	} else {
+		// In test mode, just wait for quit signal
+		<-bridge.quitChan
+	}
+
+	// Deliver the quit response and any last events before exiting
+	bridge.outbound.flush()
+	return nil
+}
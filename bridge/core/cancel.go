@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Error codes for requests that were given up on before their handler finished
+const (
+	codeCancelled        = "CANCELLED"
+	codeDeadlineExceeded = "DEADLINE_EXCEEDED"
+)
+
+// Causes of a request's context ending early
+var (
+	errRequestCancelled = errors.New("request cancelled")
+	errDeadlineExceeded = errors.New("deadline exceeded")
+)
+
+// Context returns the message's request context. It ends when the message's
+// deadline passes or a cancel message names it; handlers doing slow work
+// (fetches, decodes) should pass it on and give up once it is done.
+func (m Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// trackedRequest is a message whose handler has not returned yet
+type trackedRequest struct {
+	msg      Message // with its request context
+	cancel   context.CancelCauseFunc
+	release  context.CancelFunc // stops the deadline timer
+	answered bool               // a response has been sent; later ones are dropped
+}
+
+// requestTracker knows the requests in flight, so they can be cancelled and
+// answered exactly once
+type requestTracker struct {
+	mu       sync.Mutex
+	inFlight map[string]*trackedRequest
+}
+
+func newRequestTracker() *requestTracker {
+	return &requestTracker{inFlight: make(map[string]*trackedRequest)}
+}
+
+// startRequest gives a message its request context, applying deadlineMs.
+// A message that already carries a context, such as a gRPC call's, is also
+// cancelled when that context ends. finishRequest must be called once its
+// handler has returned.
+func (b *Bridge) startRequest(msg Message) *trackedRequest {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	release := context.CancelFunc(func() {})
+	if msg.DeadlineMs > 0 {
+		ctx, release = context.WithTimeoutCause(ctx, time.Duration(msg.DeadlineMs)*time.Millisecond, errDeadlineExceeded)
+	}
+	if msg.ctx != nil {
+		caller := msg.ctx
+		stop := context.AfterFunc(caller, func() {
+			if errors.Is(caller.Err(), context.DeadlineExceeded) {
+				cancel(errDeadlineExceeded)
+			} else {
+				cancel(errRequestCancelled)
+			}
+		})
+		releaseDeadline := release
+		release = func() {
+			stop()
+			releaseDeadline()
+		}
+	}
+	msg.ctx = ctx
+
+	request := &trackedRequest{msg: msg, cancel: cancel, release: release}
+	b.requests.mu.Lock()
+	b.requests.inFlight[msg.ID] = request
+	b.requests.mu.Unlock()
+	return request
+}
+
+// finishRequest forgets a request whose handler has returned
+func (b *Bridge) finishRequest(request *trackedRequest) {
+	request.release()
+	request.cancel(nil)
+
+	b.requests.mu.Lock()
+	defer b.requests.mu.Unlock()
+	if b.requests.inFlight[request.msg.ID] == request {
+		delete(b.requests.inFlight, request.msg.ID)
+	}
+}
+
+// abandonRequest answers a cancelled or timed-out request with a CANCELLED or
+// DEADLINE_EXCEEDED error, unless its handler has answered already. Whatever
+// the handler sends afterwards is dropped.
+func (b *Bridge) abandonRequest(request *trackedRequest) {
+	msg := request.msg
+	if !requestAbandoned(msg.Context()) {
+		return // the handler finished
+	}
+	cause := context.Cause(msg.Context())
+
+	b.requests.mu.Lock()
+	answered := request.answered
+	request.answered = true
+	b.requests.mu.Unlock()
+	if answered {
+		return
+	}
+
+	resp := Response{
+		ID:      msg.ID,
+		Success: false,
+		Error:   fmt.Sprintf("%s was cancelled", msg.Type),
+		Code:    codeCancelled,
+	}
+	if errors.Is(cause, errDeadlineExceeded) {
+		resp.Error = fmt.Sprintf("%s did not finish within %dms", msg.Type, msg.DeadlineMs)
+		resp.Code = codeDeadlineExceeded
+	}
+	logProtocol.Infof("Abandoning %s (%s): %v", msg.Type, msg.ID, cause)
+	b.deliverResponse(resp)
+}
+
+// abandonedHandler is raised by runOnMain to unwind the handler of a request
+// that was given up on; recoverMessage swallows it
+type abandonedHandler struct{}
+
+// requestAbandoned reports whether a request was cancelled or timed out, as
+// opposed to finished
+func requestAbandoned(ctx context.Context) bool {
+	cause := context.Cause(ctx)
+	return errors.Is(cause, errRequestCancelled) || errors.Is(cause, errDeadlineExceeded)
+}
+
+// cancelRequest ends a request's context. It reports false if the request is
+// not in flight, for instance because it has already finished.
+func (b *Bridge) cancelRequest(id string, cause error) bool {
+	b.requests.mu.Lock()
+	request, exists := b.requests.inFlight[id]
+	b.requests.mu.Unlock()
+	if !exists {
+		return false
+	}
+	request.cancel(cause)
+	return true
+}
+
+// claimResponse reports whether a response may be sent: the first response to
+// a request in flight wins, whether from its handler or from abandoning it
+func (b *Bridge) claimResponse(id string) bool {
+	b.requests.mu.Lock()
+	defer b.requests.mu.Unlock()
+	request, exists := b.requests.inFlight[id]
+	if !exists {
+		return true
+	}
+	if request.answered {
+		return false
+	}
+	request.answered = true
+	return true
+}
+
+// handleCancel cancels a request in flight by its message ID
+func (b *Bridge) handleCancel(msg Message) {
+	requestID := msg.Payload["requestId"].(string)
+	cancelled := b.cancelRequest(requestID, errRequestCancelled)
+
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result:  map[string]interface{}{"cancelled": cancelled},
+	})
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// testBlockingHandler waits for its payload's release channel, then tries to
+// touch the UI and answer. It reports on ran whether the UI work ran.
+func testBlockingHandler(b *Bridge, msg Message) {
+	close(msg.Payload["started"].(chan struct{}))
+	<-msg.Payload["release"].(chan struct{})
+
+	ran := false
+	defer func() { msg.Payload["ran"].(chan bool) <- ran }()
+	b.runOnMain(msg.Context(), func() { ran = true })
+	b.sendResponse(Response{ID: msg.ID, Success: true})
+}
+
+func init() {
+	if err := handlers.register(HandlerSpec{Type: "testBlocking", Handler: testBlockingHandler}, true); err != nil {
+		panic(err)
+	}
+}
+
+// routeTo sends the responses to id to a new channel
+func routeTo(b *Bridge, id string) <-chan Response {
+	collector := newChannelResponder(2, 0)
+	b.routes.add(id, collector)
+	return collector.responses
+}
+
+// expectResponse returns the next response on responses
+func expectResponse(t *testing.T, responses <-chan Response) Response {
+	t.Helper()
+	select {
+	case resp := <-responses:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response")
+		return Response{}
+	}
+}
+
+func expectNoResponse(t *testing.T, responses <-chan Response) {
+	t.Helper()
+	select {
+	case resp := <-responses:
+		t.Errorf("unexpected response %+v", resp)
+	default:
+	}
+}
+
+func TestCancelAbandonsRunningHandler(t *testing.T) {
+	b := NewBridge(true)
+	started, release, ran := make(chan struct{}), make(chan struct{}), make(chan bool, 1)
+	slow := routeTo(b, "slow")
+	cancel := routeTo(b, "cancel")
+
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		b.handleMessage(Message{ID: "slow", Type: "testBlocking", Payload: map[string]interface{}{
+			"started": started, "release": release, "ran": ran,
+		}})
+	}()
+	<-started
+
+	b.handleMessage(Message{ID: "cancel", Type: "cancel", Payload: map[string]interface{}{"requestId": "slow"}})
+	if resp := expectResponse(t, cancel); resp.Result["cancelled"] != true {
+		t.Errorf("cancel result = %v, want cancelled", resp.Result)
+	}
+
+	// The request is answered, and the next message need not wait for it
+	<-handled
+	if resp := expectResponse(t, slow); resp.Code != codeCancelled {
+		t.Errorf("response = %+v, want code %s", resp, codeCancelled)
+	}
+
+	// Once released, the handler is unwound before it touches the UI or answers
+	late := routeTo(b, "slow")
+	close(release)
+	if <-ran {
+		t.Error("the abandoned handler ran its UI work")
+	}
+	expectNoResponse(t, late)
+
+	// It has finished, so there is nothing left to cancel
+	if b.cancelRequest("slow", errRequestCancelled) {
+		t.Error("cancelled a request that had finished")
+	}
+}
+
+func TestAbandonedRequestAnsweredOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      Message
+		giveUp   func(b *Bridge, cancelCaller context.CancelFunc)
+		wantCode string
+	}{
+		{
+			name:     "cancel message",
+			msg:      Message{ID: "r", Type: "getText"},
+			giveUp:   func(b *Bridge, _ context.CancelFunc) { b.cancelRequest("r", errRequestCancelled) },
+			wantCode: codeCancelled,
+		},
+		{
+			name:     "deadlineMs",
+			msg:      Message{ID: "r", Type: "getText", DeadlineMs: 1},
+			giveUp:   func(*Bridge, context.CancelFunc) {},
+			wantCode: codeDeadlineExceeded,
+		},
+		{
+			name:     "caller cancelled",
+			msg:      Message{ID: "r", Type: "getText"},
+			giveUp:   func(_ *Bridge, cancelCaller context.CancelFunc) { cancelCaller() },
+			wantCode: codeCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBridge(true)
+			responses := routeTo(b, "r")
+			caller, cancelCaller := context.WithCancel(context.Background())
+			defer cancelCaller()
+			tt.msg.ctx = caller
+
+			request := b.startRequest(tt.msg)
+			tt.giveUp(b, cancelCaller)
+			<-request.msg.Context().Done()
+			if !requestAbandoned(request.msg.Context()) {
+				t.Fatalf("cause = %v, want the request abandoned", context.Cause(request.msg.Context()))
+			}
+
+			b.abandonRequest(request)
+			if resp := expectResponse(t, responses); resp.Code != tt.wantCode {
+				t.Errorf("response = %+v, want code %s", resp, tt.wantCode)
+			}
+
+			// The handler's own answer comes too late and is dropped
+			late := routeTo(b, "r")
+			b.sendResponse(Response{ID: "r", Success: true})
+			b.finishRequest(request)
+			expectNoResponse(t, late)
+		})
+	}
+}
+
+func TestFinishedRequestNotAbandoned(t *testing.T) {
+	b := NewBridge(true)
+	responses := routeTo(b, "r")
+
+	request := b.startRequest(Message{ID: "r", Type: "getText", DeadlineMs: 60000})
+	b.sendResponse(Response{ID: "r", Success: true})
+	b.finishRequest(request)
+
+	if resp := expectResponse(t, responses); !resp.Success {
+		t.Errorf("response = %+v, want the handler's", resp)
+	}
+
+	if requestAbandoned(request.msg.Context()) {
+		t.Error("a finished request counts as abandoned")
+	}
+	late := routeTo(b, "r")
+	b.abandonRequest(request)
+	expectNoResponse(t, late)
+
+	if b.cancelRequest("r", errRequestCancelled) {
+		t.Error("cancelled a request that had finished")
+	}
+}
+
+func TestRunOnMainUnwindsAbandonedHandler(t *testing.T) {
+	b := NewBridge(true)
+	responses := routeTo(b, "r")
+	request := b.startRequest(Message{ID: "r", Type: "getText"})
+	defer b.finishRequest(request)
+	b.cancelRequest("r", errRequestCancelled)
+
+	var steps []string
+	func() {
+		defer b.recoverMessage(request.msg)
+		b.runOnMain(request.msg.Context(), func() { steps = append(steps, "ui") })
+		steps = append(steps, "after")
+	}()
+
+	if len(steps) > 0 {
+		t.Errorf("ran %v after the request was abandoned", steps)
+	}
+	expectNoResponse(t, responses)
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Wire encodings for framed payloads. JSON is the default; a client can
+// negotiate CBOR with the hello message to send raw bytes instead of base64.
+// The Message, Response and Event shapes are the same in both encodings.
+const (
+	encodingJSON = "json"
+	encodingCBOR = "cbor"
+)
+
+// supportedEncodings lists the encodings the bridge can speak, preferred first
+var supportedEncodings = []string{encodingCBOR, encodingJSON}
+
+// cborDecMode decodes CBOR maps the way encoding/json does, so handlers see
+// map[string]interface{} payloads either way
+var cborDecMode, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+}.DecMode()
+
+// encodePayload marshals a response or event in the given encoding
+func encodePayload(encoding string, v interface{}) ([]byte, error) {
+	if encoding == encodingCBOR {
+		return cbor.Marshal(v)
+	}
+	return json.Marshal(v)
+}
+
+// decodeMessage unmarshals a message in either encoding.
+// A JSON message always starts with '{' (after optional whitespace), which a
+// CBOR map never does, so frames are recognised individually and a client can
+// switch encodings without a race.
+func decodeMessage(data []byte, msg *Message) error {
+	trimmed := strings.TrimLeft(string(data[:min(len(data), 16)]), " \t\r\n")
+	if strings.HasPrefix(trimmed, "{") {
+		return json.Unmarshal(data, msg)
+	}
+
+	if err := cborDecMode.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	// Handlers expect JSON numbers; CBOR keeps integers as integers
+	if msg.Payload != nil {
+		normalizeNumbers(msg.Payload)
+	}
+	return nil
+}
+
+// normalizeNumbers converts CBOR integers to float64, as encoding/json would produce
+func normalizeNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = normalizeNumbers(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeNumbers(item)
+		}
+		return value
+	case uint64:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	default:
+		return v
+	}
+}
+
+// negotiateEncoding picks the first of the client's preferred encodings the bridge supports
+func negotiateEncoding(preferred []interface{}) string {
+	for _, p := range preferred {
+		name, _ := p.(string)
+		for _, supported := range supportedEncodings {
+			if name == supported {
+				return name
+			}
+		}
+	}
+	return encodingJSON
+}
+
+// payloadBytes reads binary data from a payload field. CBOR clients send raw
+// bytes; JSON clients send base64, optionally as a data URI.
+func payloadBytes(payload map[string]interface{}, key string) (data []byte, present bool, err error) {
+	switch value := payload[key].(type) {
+	case []byte:
+		return value, len(value) > 0, nil
+	case string:
+		if value == "" {
+			return nil, false, nil
+		}
+		base64Data := value
+		if strings.HasPrefix(value, "data:") {
+			parts := strings.SplitN(value, ",", 2)
+			if len(parts) != 2 {
+				return nil, true, fmt.Errorf("invalid data URL format")
+			}
+			base64Data = parts[1]
+		}
+		data, err = base64.StdEncoding.DecodeString(base64Data)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid base64 data: %w", err)
+		}
+		return data, true, nil
+	default:
+		return nil, false, nil
+	}
+}
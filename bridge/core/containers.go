@@ -1,4 +1,4 @@
-package main
+package core
 
 import (
 	"fyne.io/fyne/v2"
@@ -10,7 +10,7 @@ func (b *Bridge) handleSetContent(msg Message) {
 
 	// First, check if window and widget exist (read lock)
 	b.mu.RLock()
-	win, winExists := b.windows[windowID]
+	win, winExists := b.window(windowID)
 	widget, widgetExists := b.widgets[widgetID]
 	oldContentID, hasOldContent := b.windowContent[windowID]
 	b.mu.RUnlock()
@@ -41,7 +41,7 @@ func (b *Bridge) handleSetContent(msg Message) {
 	}
 
 	// Setting window content must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		win.SetContent(widget)
 	})
 
@@ -134,7 +134,7 @@ func (b *Bridge) handleContainerAdd(msg Message) {
 	// Cast to container and add the child
 	if cont, ok := containerObj.(*fyne.Container); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			cont.Add(childObj)
 		})
 
@@ -174,7 +174,7 @@ func (b *Bridge) handleContainerRemoveAll(msg Message) {
 	// Cast to container and remove all children
 	if cont, ok := containerObj.(*fyne.Container); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			cont.Objects = nil
 		})
 
@@ -210,7 +210,7 @@ func (b *Bridge) handleContainerRefresh(msg Message) {
 	// Cast to container and refresh
 	if cont, ok := containerObj.(*fyne.Container); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			cont.Refresh()
 		})
 
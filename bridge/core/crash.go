@@ -0,0 +1,285 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// codeHandlerPanic is the Response.Code of a message whose handler panicked
+const codeHandlerPanic = "HANDLER_PANIC"
+
+// crashHistorySize is how many recent messages a crash report includes
+const crashHistorySize = 50
+
+// Limits on what the history keeps of a payload, so large uploads and images
+// are not held in memory or written to crash reports
+const (
+	historyMaxString = 256 // longer strings are cut to this many bytes
+	historyMaxItems  = 20  // longer arrays keep only their first items
+)
+
+// historyEntry is a message as it arrived, for crash reports
+type historyEntry struct {
+	Time    time.Time `json:"time"`
+	Message Message   `json:"message"`
+}
+
+// messageHistory keeps the most recent inbound messages in a ring buffer.
+// Payloads are summarized as they are added: byte fields become their length,
+// and long strings and arrays are truncated.
+type messageHistory struct {
+	mu      sync.Mutex
+	entries []historyEntry
+	next    int
+}
+
+func newMessageHistory(size int) *messageHistory {
+	return &messageHistory{entries: make([]historyEntry, 0, size)}
+}
+
+func (h *messageHistory) add(msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry := historyEntry{
+		Time: time.Now(),
+		Message: Message{
+			ID:      msg.ID,
+			Type:    msg.Type,
+			Payload: summarizePayload(msg.Payload),
+		},
+	}
+	if len(h.entries) < cap(h.entries) {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+}
+
+// snapshot returns the recorded messages, oldest first
+func (h *messageHistory) snapshot() []historyEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append(append([]historyEntry(nil), h.entries[h.next:]...), h.entries[:h.next]...)
+}
+
+// summarizePayload copies a payload for the history, keeping its shape but
+// not its bulk
+func summarizePayload(payload map[string]interface{}) map[string]interface{} {
+	if payload == nil {
+		return nil
+	}
+	summary := make(map[string]interface{}, len(payload))
+	for key, value := range payload {
+		summary[key] = summarizeValue(value)
+	}
+	return summary
+}
+
+func summarizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(v))
+	case string:
+		if len(v) > historyMaxString {
+			return fmt.Sprintf("%s... <%d bytes>", v[:historyMaxString], len(v))
+		}
+		return v
+	case map[string]interface{}:
+		return summarizePayload(v)
+	case []interface{}:
+		items := v
+		if len(items) > historyMaxItems {
+			items = items[:historyMaxItems]
+		}
+		summary := make([]interface{}, 0, len(items)+1)
+		for _, item := range items {
+			summary = append(summary, summarizeValue(item))
+		}
+		if len(v) > len(items) {
+			summary = append(summary, fmt.Sprintf("<%d more>", len(v)-len(items)))
+		}
+		return summary
+	default:
+		return value
+	}
+}
+
+// mainThreadPanic carries a panic raised on the Fyne main thread back to the
+// goroutine that asked for the work, with the stack where it happened
+type mainThreadPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p mainThreadPanic) String() string {
+	return fmt.Sprint(p.value)
+}
+
+// recoverMessage turns a panic in a message handler into an error response and
+// a bridgeError event, so one bad message does not take down every window.
+// Must be deferred directly.
+func (b *Bridge) recoverMessage(msg Message) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, abandoned := r.(abandonedHandler); abandoned {
+		logProtocol.Debugf("Stopped the handler for %s (%s), which was given up on", msg.Type, msg.ID)
+		return
+	}
+	value, stack := panicDetails(r)
+	logBridge.Errorf("Handler for %s (%s) panicked: %v\n%s", msg.Type, msg.ID, value, stack)
+
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: false,
+		Error:   fmt.Sprintf("Handler for %s panicked: %v", msg.Type, value),
+		Code:    codeHandlerPanic,
+	})
+	b.sendEvent(Event{
+		Type: "bridgeError",
+		Data: map[string]interface{}{
+			"source":      msg.Type,
+			"messageId":   msg.ID,
+			"messageType": msg.Type,
+			"error":       fmt.Sprint(value),
+			"stack":       string(stack),
+		},
+	})
+}
+
+// recoverCallback reports a panic in a Fyne event callback as a bridgeError
+// event instead of crashing the UI thread. Must be deferred directly.
+func (b *Bridge) recoverCallback(source string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	value, stack := panicDetails(r)
+	logBridge.Errorf("Callback %s panicked: %v\n%s", source, value, stack)
+
+	b.sendEvent(Event{
+		Type: "bridgeError",
+		Data: map[string]interface{}{
+			"source": source,
+			"error":  fmt.Sprint(value),
+			"stack":  string(stack),
+		},
+	})
+}
+
+// panicDetails unwraps a recovered value and finds the stack it was raised on
+func panicDetails(r interface{}) (interface{}, []byte) {
+	if p, ok := r.(mainThreadPanic); ok {
+		return p.value, p.stack
+	}
+	return r, debug.Stack()
+}
+
+// reportFatalPanic writes a crash report if the goroutine it is deferred in
+// panics, then exits. Deferred at the top of each mode, it covers the Fyne
+// event loop and anything else running on the main goroutine.
+func (b *Bridge) reportFatalPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	value, stack := panicDetails(r)
+	logBridge.Errorf("Fatal: %v\n%s", value, stack)
+
+	if path, err := b.writeCrashReport(value, stack); err != nil {
+		logBridge.Errorf("Failed to write crash report: %v", err)
+	} else {
+		logBridge.Errorf("Crash report written to %s - please attach it to your bug report", path)
+	}
+	os.Exit(2)
+}
+
+// writeCrashReport saves what is known about a fatal panic to a JSON file in
+// the crash directory and returns its path
+func (b *Bridge) writeCrashReport(value interface{}, stack []byte) (string, error) {
+	dir := b.crashDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	report := map[string]interface{}{
+		"time":            time.Now(),
+		"panic":           fmt.Sprint(value),
+		"stack":           string(stack),
+		"protocolVersion": protocolVersion,
+		"fyneVersion":     fyneVersion(),
+		"goVersion":       runtime.Version(),
+		"testMode":        b.testMode,
+		"lastMessages":    b.history.snapshot(),
+		"widgets":         b.widgetSummary(),
+		"goroutines":      allGoroutineStacks(),
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("tsyne-bridge-crash-%s-%d.json", time.Now().Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// widgetSummary counts widgets by type and lists windows. A panic can leave
+// the widget lock held, so it gives up rather than wait for it.
+func (b *Bridge) widgetSummary() map[string]interface{} {
+	if !b.mu.TryRLock() {
+		return map[string]interface{}{"unavailable": "widget registry was locked when the bridge crashed"}
+	}
+	defer b.mu.RUnlock()
+
+	byType := make(map[string]int)
+	for id := range b.widgets {
+		widgetType := b.widgetMeta[id].Type
+		if widgetType == "" {
+			widgetType = "unknown"
+		}
+		byType[widgetType]++
+	}
+
+	windows := make([]map[string]interface{}, 0, len(b.windows))
+	for id := range b.windows {
+		windows = append(windows, map[string]interface{}{
+			"id":      id,
+			"content": b.windowContent[id],
+		})
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i]["id"].(string) < windows[j]["id"].(string)
+	})
+
+	return map[string]interface{}{
+		"count":   len(b.widgets),
+		"byType":  byType,
+		"windows": windows,
+	}
+}
+
+// allGoroutineStacks returns the stacks of every goroutine
+func allGoroutineStacks() string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package core
 
 import (
 	"fmt"
@@ -13,7 +13,7 @@ func (b *Bridge) handleShowInfo(msg Message) {
 	message := msg.Payload["message"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -39,7 +39,7 @@ func (b *Bridge) handleShowError(msg Message) {
 	message := msg.Payload["message"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -66,7 +66,7 @@ func (b *Bridge) handleShowConfirm(msg Message) {
 	callbackID := msg.Payload["callbackId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -79,6 +79,7 @@ func (b *Bridge) handleShowConfirm(msg Message) {
 	}
 
 	dialog.ShowConfirm(title, message, func(confirmed bool) {
+		defer b.recoverCallback("showConfirm")
 		b.sendEvent(Event{
 			Type: "callback",
 			Data: map[string]interface{}{"callbackId": callbackID, "confirmed": confirmed},
@@ -96,7 +97,7 @@ func (b *Bridge) handleShowFileOpen(msg Message) {
 	callbackID := msg.Payload["callbackId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -109,6 +110,7 @@ func (b *Bridge) handleShowFileOpen(msg Message) {
 	}
 
 	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		defer b.recoverCallback("showFileOpen")
 		var filePath string
 		if reader != nil {
 			filePath = reader.URI().Path()
@@ -137,7 +139,7 @@ func (b *Bridge) handleShowFileSave(msg Message) {
 	fileName, _ := msg.Payload["fileName"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -150,6 +152,7 @@ func (b *Bridge) handleShowFileSave(msg Message) {
 	}
 
 	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		defer b.recoverCallback("showFileSave")
 		var filePath string
 		if writer != nil {
 			filePath = writer.URI().Path()
@@ -186,7 +189,7 @@ func (b *Bridge) handleShowCustom(msg Message) {
 	callbackID, hasCallback := msg.Payload["callbackId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	content, contentExists := b.widgets[contentID]
 	b.mu.RUnlock()
 
@@ -219,6 +222,7 @@ func (b *Bridge) handleShowCustom(msg Message) {
 	// Set callback for when dialog is closed
 	if hasCallback {
 		customDialog.SetOnClosed(func() {
+			defer b.recoverCallback("showCustom")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{"callbackId": callbackID, "closed": true},
@@ -243,7 +247,7 @@ func (b *Bridge) handleShowCustomConfirm(msg Message) {
 	callbackID := msg.Payload["callbackId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	content, contentExists := b.widgets[contentID]
 	b.mu.RUnlock()
 
@@ -275,6 +279,7 @@ func (b *Bridge) handleShowCustomConfirm(msg Message) {
 
 	// Create the custom confirm dialog
 	customDialog := dialog.NewCustomConfirm(title, confirmText, dismissText, content, func(confirmed bool) {
+		defer b.recoverCallback("showCustomConfirm")
 		b.sendEvent(Event{
 			Type: "callback",
 			Data: map[string]interface{}{"callbackId": callbackID, "confirmed": confirmed},
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"io"
+
+	"fyne.io/fyne/v2"
+)
+
+// This file is the API for Go programs that embed the bridge in-process, test
+// handlers directly, or build their own bridge binary with extra widgets.
+// Handlers use the same methods through their *Bridge argument.
+
+// HandleMessage dispatches a message as if a client had sent it. The response
+// goes to the default Responder set with SetResponder.
+func (b *Bridge) HandleMessage(msg Message) {
+	b.handleMessage(msg)
+}
+
+// Call dispatches a message and waits for its response, or for ctx to end,
+// which cancels the request
+func (b *Bridge) Call(ctx context.Context, msg Message) Response {
+	return b.callSync(ctx, msg)
+}
+
+// Serve reads framed messages from r and handles them until r is closed, as
+// stdio and socket mode do. Pair it with a Responder writing to the client.
+func (b *Bridge) Serve(r io.Reader) {
+	b.serveFramed(r)
+}
+
+// SendResponse answers a message. A handler must call it exactly once with
+// the message's ID.
+func (b *Bridge) SendResponse(resp Response) {
+	b.sendResponse(resp)
+}
+
+// SendEvent sends an event to the client and to event subscribers
+func (b *Bridge) SendEvent(event Event) {
+	b.sendEvent(event)
+}
+
+// SubscribeEvents delivers events of the given types, or all events if none
+// are given, until UnsubscribeEvents is called with the returned ID
+func (b *Bridge) SubscribeEvents(eventTypes ...string) (int, <-chan Event) {
+	return b.events.subscribe(eventTypes)
+}
+
+// UnsubscribeEvents stops a subscription and closes its channel
+func (b *Bridge) UnsubscribeEvents(id int) {
+	b.events.unsubscribe(id)
+}
+
+// RunOnMain runs fn on the Fyne main thread and waits for it. Handlers must
+// use it for anything that touches Fyne objects, passing msg.Context(): once
+// the request has been cancelled or has timed out, fn is skipped and the
+// handler is unwound. Code outside a handler passes context.Background().
+func (b *Bridge) RunOnMain(ctx context.Context, fn func()) {
+	b.runOnMain(ctx, fn)
+}
+
+// App returns the bridge's Fyne app. An embedding program that is not in
+// test mode runs its event loop with App().Run() on the main goroutine.
+func (b *Bridge) App() fyne.App {
+	return b.app
+}
+
+// AddWidget registers a widget created by a custom handler, so other messages
+// can refer to it by ID
+func (b *Bridge) AddWidget(id string, obj fyne.CanvasObject, meta WidgetMetadata) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.widgets[id] = obj
+	b.widgetMeta[id] = meta
+}
+
+// Widget looks up a widget by ID
+func (b *Bridge) Widget(id string) (fyne.CanvasObject, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	obj, exists := b.widgets[id]
+	return obj, exists
+}
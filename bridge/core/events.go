@@ -0,0 +1,95 @@
+package core
+
+import (
+	"sync"
+)
+
+// eventSubscriberBuffer is the number of events a subscriber may fall behind
+// before further events are dropped for that subscriber
+const eventSubscriberBuffer = 256
+
+// eventSubscriber is a single consumer of the event bus
+type eventSubscriber struct {
+	types map[string]bool // event types of interest; empty means all types
+	ch    chan Event
+}
+
+// eventBus fans out events raised by widgets to in-bridge subscribers such as
+// gRPC SubscribeEvents streams. It has its own lock so that publishing never
+// contends with the widget maps guarded by Bridge.mu.
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers map[int]*eventSubscriber
+	nextID      int
+	closed      bool // the bus's session has closed; no further events
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[int]*eventSubscriber),
+	}
+}
+
+// subscribe registers a subscriber for the given event types (all types if
+// none are given) and returns its ID and receive channel
+func (eb *eventBus) subscribe(eventTypes []string) (int, <-chan Event) {
+	sub := &eventSubscriber{
+		types: make(map[string]bool),
+		ch:    make(chan Event, eventSubscriberBuffer),
+	}
+	for _, t := range eventTypes {
+		if t != "" {
+			sub.types[t] = true
+		}
+	}
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.nextID++
+	if eb.closed {
+		close(sub.ch)
+		return eb.nextID, sub.ch
+	}
+	eb.subscribers[eb.nextID] = sub
+	return eb.nextID, sub.ch
+}
+
+// unsubscribe removes a subscriber and closes its channel
+func (eb *eventBus) unsubscribe(id int) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	if sub, exists := eb.subscribers[id]; exists {
+		delete(eb.subscribers, id)
+		close(sub.ch)
+	}
+}
+
+// close removes every subscriber, closing their channels. Later
+// subscribers get a channel that is already closed.
+func (eb *eventBus) close() {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.closed = true
+	for id, sub := range eb.subscribers {
+		delete(eb.subscribers, id)
+		close(sub.ch)
+	}
+}
+
+// publish delivers an event to every interested subscriber.
+// A subscriber that is not keeping up has the event dropped rather than
+// blocking the Fyne callback that raised it.
+func (eb *eventBus) publish(event Event) {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	for id, sub := range eb.subscribers {
+		if len(sub.types) > 0 && !sub.types[event.Type] {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			logEvents.Warnf("Subscriber %d is full, dropping %s event", id, event.Type)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/paul-hammant/tsyne/bridge/proto"
+)
+
+// Token scopes, from least to most privileged. A token may use every
+// message type whose HandlerSpec.Scope its own scope covers.
+const (
+	ScopeRead     = "read"     // inspect widgets and subscribe to events
+	ScopeInteract = "interact" // also click, type and drag as a user would
+	ScopeFull     = "full"     // everything, including building UI, quitting and managing tokens
+)
+
+// codePermissionDenied marks a message the caller's token may not send
+const codePermissionDenied = "PERMISSION_DENIED"
+
+// primaryTokenID names the token the bridge was started with
+const primaryTokenID = "primary"
+
+// scopeRank orders scopes; an unknown or empty scope ranks as full, so a
+// handler registered without a scope is never opened up by accident
+func scopeRank(scope string) int {
+	switch scope {
+	case ScopeRead:
+		return 1
+	case ScopeInteract:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// requiredScope is the scope a message type needs
+func requiredScope(spec HandlerSpec) string {
+	if spec.Scope == "" {
+		return ScopeFull
+	}
+	return spec.Scope
+}
+
+// scopeAllows reports whether a token with the granted scope may use
+// something that needs the required scope
+func scopeAllows(granted, required string) bool {
+	return scopeRank(granted) >= scopeRank(required)
+}
+
+// messageScope is the scope needed to send a message. A batch needs the
+// widest scope of its steps.
+func messageScope(msgType string, payload map[string]interface{}) string {
+	spec, exists := handlers.lookup(msgType)
+	if !exists {
+		return ScopeFull
+	}
+	if msgType != "batch" {
+		return requiredScope(spec)
+	}
+
+	required := ScopeRead
+	steps, _ := payload["messages"].([]interface{})
+	for _, raw := range steps {
+		step, _ := raw.(map[string]interface{})
+		stepType, _ := step["type"].(string)
+		if stepScope := messageScope(stepType, nil); scopeRank(stepScope) > scopeRank(required) {
+			required = stepScope
+		}
+	}
+	return required
+}
+
+// methodScope is the scope needed to call a gRPC method. The typed methods
+// share the scope of the message type they send; Invoke, InvokeStream and
+// Session check each message they carry instead.
+func methodScope(fullMethod string) string {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	switch method {
+	case "SubscribeEvents", "Invoke", "InvokeStream", "Session", "ListSessions":
+		return ScopeRead
+	case "CreateToken", "RevokeToken", "ListTokens", "OpenSession", "CloseSession":
+		return ScopeFull
+	}
+
+	first, size := utf8.DecodeRuneInString(method)
+	if spec, exists := handlers.lookup(string(unicode.ToLower(first)) + method[size:]); exists {
+		return requiredScope(spec)
+	}
+	return ScopeFull
+}
+
+// grpcToken is one token accepted by the gRPC service
+type grpcToken struct {
+	id      string
+	name    string
+	scope   string
+	secret  string
+	expires time.Time     // zero for never
+	revoked chan struct{} // closed when the token is revoked
+	creator *grpcToken    // nil for the primary token
+}
+
+// alive reports whether the token may still be used
+func (t *grpcToken) alive() bool {
+	select {
+	case <-t.revoked:
+		return false
+	default:
+		return t.expires.IsZero() || time.Now().Before(t.expires)
+	}
+}
+
+// bind returns a context that ends when ctx does, or when the token expires
+// or is revoked, so streams opened with it end too
+func (t *grpcToken) bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.expires.IsZero() {
+		return bindDone(ctx, t.revoked)
+	}
+	ctx, cancelDeadline := context.WithDeadline(ctx, t.expires)
+	ctx, cancel := bindDone(ctx, t.revoked)
+	return ctx, func() {
+		cancel()
+		cancelDeadline()
+	}
+}
+
+// bindDone returns a context that also ends when done is closed
+func bindDone(ctx context.Context, done <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// actsFor reports whether the token may act for the token with the given ID:
+// it is that token, was created by it directly or indirectly, or is the primary
+// token, which acts for every token
+func (t *grpcToken) actsFor(id string) bool {
+	if t.id == primaryTokenID {
+		return true
+	}
+	for token := t; token != nil; token = token.creator {
+		if token.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *grpcToken) info() *pb.TokenInfo {
+	info := &pb.TokenInfo{Id: t.id, Scope: t.scope, Name: t.name}
+	if !t.expires.IsZero() {
+		info.ExpiresAt = t.expires.Unix()
+	}
+	return info
+}
+
+// tokenStore holds the gRPC tokens. The primary token is full scope, never
+// expires and cannot be revoked, so the bridge's owner cannot lock itself out.
+type tokenStore struct {
+	mu       sync.Mutex
+	bySecret map[string]*grpcToken
+	nextID   int
+}
+
+func newTokenStore(primarySecret string) *tokenStore {
+	s := &tokenStore{bySecret: make(map[string]*grpcToken)}
+	s.bySecret[primarySecret] = &grpcToken{
+		id:      primaryTokenID,
+		name:    "started with the bridge",
+		scope:   ScopeFull,
+		secret:  primarySecret,
+		revoked: make(chan struct{}),
+	}
+	return s
+}
+
+// authenticate finds the live token for a secret
+func (s *tokenStore) authenticate(secret string) (*grpcToken, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, exists := s.bySecret[secret]
+	if !exists {
+		return nil, false
+	}
+	if !token.alive() {
+		delete(s.bySecret, secret)
+		return nil, false
+	}
+	return token, true
+}
+
+// create adds a token. It may not outlive the token that creates it.
+func (s *tokenStore) create(creator *grpcToken, name, scope string, ttl time.Duration) (*grpcToken, error) {
+	if scope != ScopeRead && scope != ScopeInteract && scope != ScopeFull {
+		return nil, fmt.Errorf("unknown scope %q, expected %s, %s or %s", scope, ScopeRead, ScopeInteract, ScopeFull)
+	}
+	if ttl < 0 {
+		return nil, fmt.Errorf("ttl_seconds must not be negative")
+	}
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	if !creator.expires.IsZero() && (expires.IsZero() || expires.After(creator.expires)) {
+		expires = creator.expires
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	token := &grpcToken{
+		id:      "token_" + strconv.Itoa(s.nextID),
+		name:    name,
+		scope:   scope,
+		secret:  generateSecureToken(32),
+		expires: expires,
+		revoked: make(chan struct{}),
+		creator: creator,
+	}
+	s.bySecret[token.secret] = token
+	return token, nil
+}
+
+// revoke invalidates a token at once, ending its open streams
+func (s *tokenStore) revoke(id string) error {
+	if id == primaryTokenID {
+		return fmt.Errorf("the primary token cannot be revoked")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for secret, token := range s.bySecret {
+		if token.id == id {
+			delete(s.bySecret, secret)
+			close(token.revoked)
+			return nil
+		}
+	}
+	return fmt.Errorf("no token %s", id)
+}
+
+// list returns the live tokens, ordered by ID
+func (s *tokenStore) list() []*grpcToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens := make([]*grpcToken, 0, len(s.bySecret))
+	for secret, token := range s.bySecret {
+		if !token.alive() {
+			delete(s.bySecret, secret)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].id < tokens[j].id })
+	return tokens
+}
+
+// grpcTokenKey carries the caller's token in a request context
+type grpcTokenKey struct{}
+
+// tokenFromContext returns the token a gRPC call was made with
+func tokenFromContext(ctx context.Context) (*grpcToken, bool) {
+	token, ok := ctx.Value(grpcTokenKey{}).(*grpcToken)
+	return token, ok
+}
+
+// authorize checks a call's token against the scope its method needs
+func (s *tokenStore) authorize(ctx context.Context, fullMethod string) (*grpcToken, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	secrets := md.Get("authorization")
+	if len(secrets) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	token, ok := s.authenticate(secrets[0])
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	if required := methodScope(fullMethod); !scopeAllows(token.scope, required) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s needs %s scope", fullMethod, required)
+	}
+	return token, nil
+}
+
+// tokenExempt reports whether a gRPC method may be called without the token.
+// Health checks and reflection carry no bridge data, and probes and stock
+// tools such as grpcurl can use them without being configured with it.
+func tokenExempt(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
+// tokenAuthInterceptor validates the auth token for gRPC requests
+func tokenAuthInterceptor(tokens *tokenStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if tokenExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		token, err := tokens.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, grpcTokenKey{}, token), req)
+	}
+}
+
+// tokenStreamInterceptor validates the auth token for streaming gRPC requests
+func tokenStreamInterceptor(tokens *tokenStore) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if tokenExempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		token, err := tokens.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		ctx := context.WithValue(ss.Context(), grpcTokenKey{}, token)
+		if token.id == primaryTokenID {
+			return handler(srv, &boundStream{ServerStream: ss, ctx: ctx})
+		}
+		return serveBound(srv, ss, handler, ctx, token.bind, errTokenEnded)
+	}
+}
+
+// errTokenEnded ends a stream whose token expired or was revoked while it was open
+var errTokenEnded = status.Error(codes.Unauthenticated, "token expired or revoked")
+
+// serveBound runs a stream handler with ctx bound by bind, ending the stream
+// with ended if the bound context ends before the client goes away
+func serveBound(srv interface{}, ss grpc.ServerStream, handler grpc.StreamHandler, ctx context.Context,
+	bind func(context.Context) (context.Context, context.CancelFunc), ended error) error {
+	ctx, cancel := bind(ctx)
+	defer cancel()
+	err := handler(srv, &boundStream{ServerStream: ss, ctx: ctx, ended: ended})
+	if ss.Context().Err() == nil && ctx.Err() != nil {
+		return ended
+	}
+	return err
+}
+
+// boundStream gives a stream's handler a context that may end before the
+// client goes away, when the token or session it was opened with ends
+type boundStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	ended error // returned by RecvMsg once ctx ends; nil if it only ends with the stream
+}
+
+func (s *boundStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg gives up when the context ends, so a handler blocked waiting for
+// the client's next message returns. The abandoned receive fails once the
+// handler has returned and gRPC closes the stream.
+func (s *boundStream) RecvMsg(m interface{}) error {
+	if s.ended == nil {
+		return s.ServerStream.RecvMsg(m)
+	}
+	received := make(chan error, 1)
+	go func() { received <- s.ServerStream.RecvMsg(m) }()
+	select {
+	case err := <-received:
+		return err
+	case <-s.ctx.Done():
+		return s.ended
+	}
+}
+
+// checkMessageScope refuses a message carried by Invoke, InvokeStream or
+// Session that the caller's token may not send, or if the token has since
+// expired or been revoked
+func checkMessageScope(ctx context.Context, req *pb.InvokeRequest, payload map[string]interface{}) *pb.InvokeResponse {
+	token, ok := tokenFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if !token.alive() {
+		return &pb.InvokeResponse{
+			Id:      req.Id,
+			Success: false,
+			Error:   "Token expired or revoked",
+			Code:    codePermissionDenied,
+		}
+	}
+	if required := messageScope(req.Type, payload); !scopeAllows(token.scope, required) {
+		return &pb.InvokeResponse{
+			Id:      req.Id,
+			Success: false,
+			Error:   fmt.Sprintf("%s needs %s scope", req.Type, required),
+			Code:    codePermissionDenied,
+		}
+	}
+	return nil
+}
+
+// CreateToken issues a scoped token, optionally expiring
+func (s *grpcBridgeService) CreateToken(ctx context.Context, req *pb.CreateTokenRequest) (*pb.TokenInfo, error) {
+	creator, _ := tokenFromContext(ctx)
+	token, err := s.tokens.create(creator, req.Name, req.Scope, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	logGrpc.Infof("Created %s token %s (%q) for %s", token.scope, token.id, token.name, creator.id)
+
+	info := token.info()
+	info.Token = token.secret
+	return info, nil
+}
+
+// RevokeToken invalidates a token and ends the streams opened with it
+func (s *grpcBridgeService) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.Response, error) {
+	if err := s.tokens.revoke(req.Id); err != nil {
+		return &pb.Response{Success: false, Error: err.Error()}, nil
+	}
+	logGrpc.Infof("Revoked token %s", req.Id)
+	return &pb.Response{Success: true}, nil
+}
+
+// ListTokens lists the tokens that are still valid, without their secrets
+func (s *grpcBridgeService) ListTokens(ctx context.Context, req *pb.ListTokensRequest) (*pb.ListTokensResponse, error) {
+	resp := &pb.ListTokensResponse{}
+	for _, token := range s.tokens.list() {
+		resp.Tokens = append(resp.Tokens, token.info())
+	}
+	return resp, nil
+}
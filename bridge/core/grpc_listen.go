@@ -0,0 +1,149 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	pb "github.com/paul-hammant/tsyne/bridge/proto"
+)
+
+// defaultGrpcAddress keeps the gRPC service local, on a port the OS picks
+const defaultGrpcAddress = "localhost:0"
+
+// GrpcOptions configures the gRPC listener
+type GrpcOptions struct {
+	Address      string // host:port to listen on; port 0 picks a free port
+	Token        string // token clients must present, generated if empty
+	CertFile     string // server certificate (PEM); with KeyFile, enables TLS
+	KeyFile      string // server private key (PEM)
+	ClientCAFile string // CA bundle (PEM) for client certificates; enables mTLS
+}
+
+// tls reports whether the options ask for TLS
+func (o GrpcOptions) tls() bool {
+	return o.CertFile != "" || o.KeyFile != ""
+}
+
+// grpcTLSConfig builds the server TLS configuration, or returns nil when TLS
+// is off. With a client CA, every client must present a certificate it signed.
+func grpcTLSConfig(opts GrpcOptions) (*tls.Config, error) {
+	if !opts.tls() {
+		if opts.ClientCAFile != "" {
+			return nil, fmt.Errorf("a client CA needs a server certificate and key")
+		}
+		return nil, nil
+	}
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, fmt.Errorf("TLS needs both a certificate and a key")
+	}
+
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if opts.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in client CA %s", opts.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// grpcServer is the bridge's gRPC listener with the services it carries
+type grpcServer struct {
+	server   *grpc.Server
+	health   *health.Server
+	listener net.Listener
+}
+
+// listenGrpc binds the gRPC listener and registers BridgeService, the
+// standard health service and server reflection. The listener is bound on
+// return, so clients may connect as soon as its address is announced.
+func listenGrpc(opts GrpcOptions, token string, bridge *Bridge) (*grpcServer, error) {
+	tlsConfig, err := grpcTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	address := opts.Address
+	if address == "" {
+		address = defaultGrpcAddress
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil && !isLoopback(listener.Addr()) {
+		logGrpc.Warnf("Listening on %s without TLS; the token is sent in the clear", listener.Addr())
+	}
+
+	tokens := newTokenStore(token)
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tokenAuthInterceptor(tokens), sessionInterceptor(bridge.sessions)),
+		grpc.ChainStreamInterceptor(tokenStreamInterceptor(tokens), sessionStreamInterceptor(bridge.sessions)),
+	}
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	pb.RegisterBridgeServiceServer(server, &grpcBridgeService{
+		bridge:  bridge,
+		tokens:  tokens,
+		callers: make(map[string]*invokeCallers),
+	})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	// Serving from here on: the bridge is built and the listener is bound
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(pb.BridgeService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return &grpcServer{server: server, health: healthServer, listener: listener}, nil
+}
+
+// serve accepts connections until stop is called
+func (g *grpcServer) serve() error {
+	logGrpc.Infof("Server listening on %s", g.listener.Addr())
+	return g.server.Serve(g.listener)
+}
+
+// stop reports NOT_SERVING to health checks and closes every connection
+func (g *grpcServer) stop() {
+	g.health.Shutdown()
+	g.server.Stop()
+}
+
+// port is the TCP port actually listened on
+func (g *grpcServer) port() int {
+	return g.listener.Addr().(*net.TCPAddr).Port
+}
+
+// isLoopback reports whether a listener address only accepts local connections
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
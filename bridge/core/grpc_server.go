@@ -0,0 +1,886 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	pb "github.com/paul-hammant/tsyne/bridge/proto"
+)
+
+// grpcBridgeService implements the BridgeService gRPC interface
+type grpcBridgeService struct {
+	pb.UnimplementedBridgeServiceServer
+	bridge    *Bridge
+	tokens    *tokenStore
+	callersMu sync.Mutex
+	callers   map[string]*invokeCallers // session and token ID -> their running Invoke calls
+}
+
+// invokeCallers maps the IDs of the Invoke calls one session and token have
+// running. It is dropped when the last of them finishes.
+type invokeCallers struct {
+	ids   *callerIDs
+	calls int
+}
+
+// CreateWindow creates a new window
+func (s *grpcBridgeService) CreateWindow(ctx context.Context, req *pb.CreateWindowRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateWindow: %s", req.WindowId)
+
+	msg := Message{
+		Type: "createWindow",
+		Payload: map[string]interface{}{
+			"id":        req.WindowId,
+			"title":     req.Title,
+			"width":     float64(req.Width),
+			"height":    float64(req.Height),
+			"fixedSize": req.FixedSize,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// ShowWindow shows a window
+func (s *grpcBridgeService) ShowWindow(ctx context.Context, req *pb.ShowWindowRequest) (*pb.Response, error) {
+	logGrpc.Debugf("ShowWindow: %s", req.WindowId)
+
+	msg := Message{
+		Type: "showWindow",
+		Payload: map[string]interface{}{
+			"windowId": req.WindowId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// SetContent sets window content
+func (s *grpcBridgeService) SetContent(ctx context.Context, req *pb.SetContentRequest) (*pb.Response, error) {
+	logGrpc.Debugf("SetContent: window=%s, widget=%s", req.WindowId, req.WidgetId)
+
+	msg := Message{
+		Type: "setContent",
+		Payload: map[string]interface{}{
+			"windowId": req.WindowId,
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// ResizeWindow resizes a window
+func (s *grpcBridgeService) ResizeWindow(ctx context.Context, req *pb.ResizeWindowRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "resizeWindow",
+		Payload: map[string]interface{}{
+			"windowId": req.WindowId,
+			"width":    float64(req.Width),
+			"height":   float64(req.Height),
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// SetWindowTitle sets window title
+func (s *grpcBridgeService) SetWindowTitle(ctx context.Context, req *pb.SetWindowTitleRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "setWindowTitle",
+		Payload: map[string]interface{}{
+			"windowId": req.WindowId,
+			"title":    req.Title,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CenterWindow centers a window
+func (s *grpcBridgeService) CenterWindow(ctx context.Context, req *pb.CenterWindowRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "centerWindow",
+		Payload: map[string]interface{}{
+			"windowId": req.WindowId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// SetWindowFullScreen sets window fullscreen
+func (s *grpcBridgeService) SetWindowFullScreen(ctx context.Context, req *pb.SetWindowFullScreenRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "setWindowFullScreen",
+		Payload: map[string]interface{}{
+			"windowId":   req.WindowId,
+			"fullscreen": req.Fullscreen,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateImage creates an image widget
+func (s *grpcBridgeService) CreateImage(ctx context.Context, req *pb.CreateImageRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateImage: %s", req.WidgetId)
+
+	payload := map[string]interface{}{
+		"id":     req.WidgetId,
+		"width":  float64(req.Width),
+		"height": float64(req.Height),
+	}
+
+	// Handle source (inline data or resource reference)
+	switch src := req.Source.(type) {
+	case *pb.CreateImageRequest_InlineData:
+		// Convert bytes to a base64 data URI, which handleCreateImage accepts as a path
+		payload["path"] = fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(src.InlineData))
+	case *pb.CreateImageRequest_ResourceName:
+		payload["resource"] = src.ResourceName
+	}
+
+	// Add callbacks if present
+	if req.CallbackId != "" {
+		payload["callbackId"] = req.CallbackId
+	}
+	if req.DragCallbackId != "" {
+		payload["onDragCallbackId"] = req.DragCallbackId
+	}
+	if req.DoubleTapCallbackId != "" {
+		payload["doubleTapCallbackId"] = req.DoubleTapCallbackId
+	}
+
+	msg := Message{
+		Type:    "createImage",
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateLabel creates a label widget
+func (s *grpcBridgeService) CreateLabel(ctx context.Context, req *pb.CreateLabelRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateLabel: %s", req.WidgetId)
+
+	payload := map[string]interface{}{
+		"id":   req.WidgetId,
+		"text": req.Text,
+	}
+
+	if req.Bold {
+		payload["bold"] = true
+	}
+	if req.Alignment != 0 {
+		payload["alignment"] = float64(req.Alignment)
+	}
+
+	msg := Message{
+		Type:    "createLabel",
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateButton creates a button widget
+func (s *grpcBridgeService) CreateButton(ctx context.Context, req *pb.CreateButtonRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateButton: %s", req.WidgetId)
+
+	payload := map[string]interface{}{
+		"id":   req.WidgetId,
+		"text": req.Text,
+	}
+
+	if req.CallbackId != "" {
+		payload["callbackId"] = req.CallbackId
+	}
+	if req.Important {
+		payload["importance"] = "high"
+	}
+
+	msg := Message{
+		Type:    "createButton",
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateEntry creates an entry widget
+func (s *grpcBridgeService) CreateEntry(ctx context.Context, req *pb.CreateEntryRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateEntry: %s", req.WidgetId)
+
+	payload := map[string]interface{}{
+		"id": req.WidgetId,
+	}
+
+	if req.Placeholder != "" {
+		payload["placeholder"] = req.Placeholder
+	}
+	if req.CallbackId != "" {
+		payload["callbackId"] = req.CallbackId
+	}
+	if req.Width != 0 {
+		payload["minWidth"] = float64(req.Width)
+	}
+
+	msgType := "createEntry"
+	if req.Multiline {
+		msgType = "createMultiLineEntry"
+	} else if req.Password {
+		msgType = "createPasswordEntry"
+	}
+
+	msg := Message{
+		Type:    msgType,
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateVBox creates a vertical box container
+func (s *grpcBridgeService) CreateVBox(ctx context.Context, req *pb.CreateVBoxRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateVBox: %s", req.WidgetId)
+
+	msg := Message{
+		Type: "createVBox",
+		Payload: map[string]interface{}{
+			"id": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateHBox creates a horizontal box container
+func (s *grpcBridgeService) CreateHBox(ctx context.Context, req *pb.CreateHBoxRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateHBox: %s", req.WidgetId)
+
+	msg := Message{
+		Type: "createHBox",
+		Payload: map[string]interface{}{
+			"id": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateCheckbox creates a checkbox widget
+func (s *grpcBridgeService) CreateCheckbox(ctx context.Context, req *pb.CreateCheckboxRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateCheckbox: %s", req.WidgetId)
+
+	payload := map[string]interface{}{
+		"id":      req.WidgetId,
+		"text":    req.Text,
+		"checked": req.Checked,
+	}
+
+	if req.CallbackId != "" {
+		payload["callbackId"] = req.CallbackId
+	}
+
+	msg := Message{
+		Type:    "createCheckbox",
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// CreateSelect creates a select widget
+func (s *grpcBridgeService) CreateSelect(ctx context.Context, req *pb.CreateSelectRequest) (*pb.Response, error) {
+	logGrpc.Debugf("CreateSelect: %s", req.WidgetId)
+
+	// Handlers expect JSON-shaped payloads, so options become []interface{}
+	options := make([]interface{}, len(req.Options))
+	for i, opt := range req.Options {
+		options[i] = opt
+	}
+
+	payload := map[string]interface{}{
+		"id":       req.WidgetId,
+		"options":  options,
+		"selected": float64(req.Selected),
+	}
+
+	if req.CallbackId != "" {
+		payload["callbackId"] = req.CallbackId
+	}
+
+	msg := Message{
+		Type:    "createSelect",
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// RegisterResource registers a reusable resource
+func (s *grpcBridgeService) RegisterResource(ctx context.Context, req *pb.RegisterResourceRequest) (*pb.Response, error) {
+	logGrpc.Debugf("RegisterResource: %s (%d bytes)", req.Name, len(req.Data))
+
+	// Convert raw bytes to base64 for the existing handler
+	base64Data := base64.StdEncoding.EncodeToString(req.Data)
+
+	msg := Message{
+		Type: "registerResource",
+		Payload: map[string]interface{}{
+			"name": req.Name,
+			"data": base64Data,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// UnregisterResource unregisters a resource
+func (s *grpcBridgeService) UnregisterResource(ctx context.Context, req *pb.UnregisterResourceRequest) (*pb.Response, error) {
+	logGrpc.Debugf("UnregisterResource: %s", req.Name)
+
+	msg := Message{
+		Type: "unregisterResource",
+		Payload: map[string]interface{}{
+			"name": req.Name,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// UpdateImage updates an image widget
+func (s *grpcBridgeService) UpdateImage(ctx context.Context, req *pb.UpdateImageRequest) (*pb.Response, error) {
+	logGrpc.Debugf("UpdateImage: %s", req.WidgetId)
+
+	payload := map[string]interface{}{
+		"widgetId": req.WidgetId,
+	}
+
+	// Handle source (inline data or resource reference)
+	switch src := req.Source.(type) {
+	case *pb.UpdateImageRequest_InlineData:
+		payload["imageData"] = fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(src.InlineData))
+	case *pb.UpdateImageRequest_ResourceName:
+		payload["resource"] = src.ResourceName
+	}
+
+	msg := Message{
+		Type:    "updateImage",
+		Payload: payload,
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// SetText sets widget text
+func (s *grpcBridgeService) SetText(ctx context.Context, req *pb.SetTextRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "setText",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+			"text":     req.Text,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// GetText gets widget text
+func (s *grpcBridgeService) GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse, error) {
+	msg := Message{
+		Type: "getText",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+	text, _ := resp.Result["text"].(string)
+
+	return &pb.GetTextResponse{
+		Success: resp.Success,
+		Error:   resp.Error,
+		Text:    text,
+	}, nil
+}
+
+// SetProgress sets progress value
+func (s *grpcBridgeService) SetProgress(ctx context.Context, req *pb.SetProgressRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "setProgress",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+			"value":    req.Value,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// GetProgress gets progress value
+func (s *grpcBridgeService) GetProgress(ctx context.Context, req *pb.GetProgressRequest) (*pb.GetProgressResponse, error) {
+	msg := Message{
+		Type: "getProgress",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+
+	return &pb.GetProgressResponse{
+		Success: resp.Success,
+		Error:   resp.Error,
+		Value:   resultFloat(resp.Result, "value"),
+	}, nil
+}
+
+// SetChecked sets checkbox checked state
+func (s *grpcBridgeService) SetChecked(ctx context.Context, req *pb.SetCheckedRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "setChecked",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+			"checked":  req.Checked,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// GetChecked gets checkbox checked state
+func (s *grpcBridgeService) GetChecked(ctx context.Context, req *pb.GetCheckedRequest) (*pb.GetCheckedResponse, error) {
+	msg := Message{
+		Type: "getChecked",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+	checked, _ := resp.Result["checked"].(bool)
+
+	return &pb.GetCheckedResponse{
+		Success: resp.Success,
+		Error:   resp.Error,
+		Checked: checked,
+	}, nil
+}
+
+// ClickWidget simulates clicking a widget
+func (s *grpcBridgeService) ClickWidget(ctx context.Context, req *pb.ClickWidgetRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "clickWidget",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// TypeText simulates typing text
+func (s *grpcBridgeService) TypeText(ctx context.Context, req *pb.TypeTextRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "typeText",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+			"text":     req.Text,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// DoubleTapWidget simulates double-tapping a widget
+func (s *grpcBridgeService) DoubleTapWidget(ctx context.Context, req *pb.DoubleTapWidgetRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "doubleTapWidget",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// RightClickWidget simulates right-clicking a widget
+func (s *grpcBridgeService) RightClickWidget(ctx context.Context, req *pb.RightClickWidgetRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "rightClickWidget",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// DragWidget simulates dragging a widget
+func (s *grpcBridgeService) DragWidget(ctx context.Context, req *pb.DragWidgetRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "dragWidget",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+			"x":        float64(req.DeltaX),
+			"y":        float64(req.DeltaY),
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// RegisterCustomId registers a custom ID for a widget
+func (s *grpcBridgeService) RegisterCustomId(ctx context.Context, req *pb.RegisterCustomIdRequest) (*pb.Response, error) {
+	msg := Message{
+		Type: "registerCustomId",
+		Payload: map[string]interface{}{
+			"customId": req.CustomId,
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// FindWidget finds widgets by selector
+func (s *grpcBridgeService) FindWidget(ctx context.Context, req *pb.FindWidgetRequest) (*pb.FindWidgetResponse, error) {
+	msg := Message{
+		Type: "findWidget",
+		Payload: map[string]interface{}{
+			"selector": req.Selector,
+			"type":     req.Type,
+		},
+	}
+
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+	widgetIDs, _ := resp.Result["widgetIds"].([]string)
+
+	return &pb.FindWidgetResponse{
+		Success:   resp.Success,
+		Error:     resp.Error,
+		WidgetIds: widgetIDs,
+	}, nil
+}
+
+// GetWidgetInfo gets widget information
+func (s *grpcBridgeService) GetWidgetInfo(ctx context.Context, req *pb.GetWidgetInfoRequest) (*pb.WidgetInfoResponse, error) {
+	msg := Message{
+		Type: "getWidgetInfo",
+		Payload: map[string]interface{}{
+			"widgetId": req.WidgetId,
+		},
+	}
+
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+	if !resp.Success {
+		return &pb.WidgetInfoResponse{
+			Success: false,
+			Error:   resp.Error,
+			Id:      req.WidgetId,
+		}, nil
+	}
+
+	widgetType, _ := resp.Result["type"].(string)
+	text, _ := resp.Result["text"].(string)
+	visible, _ := resp.Result["visible"].(bool)
+	enabled, _ := resp.Result["enabled"].(bool)
+
+	return &pb.WidgetInfoResponse{
+		Success: true,
+		Id:      req.WidgetId,
+		Type:    widgetType,
+		Text:    text,
+		X:       float32(resultFloat(resp.Result, "x")),
+		Y:       float32(resultFloat(resp.Result, "y")),
+		Width:   float32(resultFloat(resp.Result, "width")),
+		Height:  float32(resultFloat(resp.Result, "height")),
+		Visible: visible,
+		Enabled: enabled,
+	}, nil
+}
+
+// GetAllWidgets gets all widgets
+func (s *grpcBridgeService) GetAllWidgets(ctx context.Context, req *pb.GetAllWidgetsRequest) (*pb.GetAllWidgetsResponse, error) {
+	msg := Message{
+		Type: "getAllWidgets",
+	}
+
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+	widgetInfos, _ := resp.Result["widgets"].([]map[string]interface{})
+
+	widgets := make([]*pb.WidgetInfo, 0, len(widgetInfos))
+	for _, info := range widgetInfos {
+		id, _ := info["id"].(string)
+		widgetType, _ := info["type"].(string)
+		text, _ := info["text"].(string)
+		widgets = append(widgets, &pb.WidgetInfo{
+			Id:   id,
+			Type: widgetType,
+			Text: text,
+		})
+	}
+
+	return &pb.GetAllWidgetsResponse{
+		Success: resp.Success,
+		Error:   resp.Error,
+		Widgets: widgets,
+	}, nil
+}
+
+// SubscribeEvents subscribes to events (streaming)
+func (s *grpcBridgeService) SubscribeEvents(req *pb.EventSubscription, stream pb.BridgeService_SubscribeEventsServer) error {
+	logGrpc.Debugf("SubscribeEvents: %v", req.EventTypes)
+
+	bridge := s.bridgeFor(stream.Context())
+	subID, events := bridge.events.subscribe(req.EventTypes)
+	defer bridge.events.unsubscribe(subID)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			logGrpc.Debugf("SubscribeEvents: subscriber %d disconnected", subID)
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toProtoEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toProtoEvent converts a bridge event to its protobuf form
+func toProtoEvent(event Event) *pb.Event {
+	return &pb.Event{
+		Type:     event.Type,
+		WidgetId: event.WidgetID,
+		Data:     toProtoStringMap(event.Data),
+	}
+}
+
+// toProtoStringMap flattens a JSON-style map into the string map used by protobuf messages.
+// Non-string values are JSON-encoded.
+func toProtoStringMap(values map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		if str, ok := value.(string); ok {
+			result[key] = str
+			continue
+		}
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			logGrpc.Warnf("Error encoding value %q: %v", key, err)
+			continue
+		}
+		result[key] = string(jsonValue)
+	}
+	return result
+}
+
+// resultFloat reads a numeric result field regardless of the numeric type the handler used
+func resultFloat(result map[string]interface{}, key string) float64 {
+	switch v := result[key].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// invoke dispatches a message to its handler and converts the handler's response
+func (s *grpcBridgeService) invoke(ctx context.Context, msg Message) *pb.Response {
+	resp := s.bridgeFor(ctx).callSync(ctx, msg)
+	return &pb.Response{
+		Success: resp.Success,
+		Error:   resp.Error,
+		Result:  toProtoStringMap(resp.Result),
+		Code:    resp.Code,
+		Field:   resp.Field,
+	}
+}
+
+// Quit quits the application
+func (s *grpcBridgeService) Quit(ctx context.Context, req *pb.QuitRequest) (*pb.Response, error) {
+	logGrpc.Debugf("Quit")
+
+	msg := Message{
+		Type:    "quit",
+		Payload: map[string]interface{}{},
+	}
+
+	return s.invoke(ctx, msg), nil
+}
+
+// Invoke dispatches any stdio message type through the same handlers as stdio mode.
+// A cancel names the ID of another Invoke call made in the same session with
+// the same token.
+func (s *grpcBridgeService) Invoke(ctx context.Context, req *pb.InvokeRequest) (*pb.InvokeResponse, error) {
+	logGrpc.Debugf("Invoke: %s", req.Type)
+	callers, release := s.invokeCallers(ctx)
+	defer release()
+	return s.invokeMessage(ctx, req, callers), nil
+}
+
+// invokeCallers returns the caller IDs shared by the Invoke calls made in one
+// session with one token, and a release to call when this call finishes
+func (s *grpcBridgeService) invokeCallers(ctx context.Context) (*callerIDs, func()) {
+	key := s.bridgeFor(ctx).session.id
+	if token, ok := tokenFromContext(ctx); ok {
+		key += "/" + token.id
+	}
+
+	s.callersMu.Lock()
+	defer s.callersMu.Unlock()
+	callers, exists := s.callers[key]
+	if !exists {
+		callers = &invokeCallers{ids: newCallerIDs()}
+		s.callers[key] = callers
+	}
+	callers.calls++
+
+	release := func() {
+		s.callersMu.Lock()
+		defer s.callersMu.Unlock()
+		callers.calls--
+		if callers.calls == 0 {
+			delete(s.callers, key)
+		}
+	}
+	return callers.ids, release
+}
+
+// InvokeStream is the streaming form of Invoke.
+// Requests are handled one at a time in the order received, and each response
+// carries the ID of the request it answers. A cancel is handled as soon as it
+// is read, so it can reach the request it names while that is running.
+func (s *grpcBridgeService) InvokeStream(stream pb.BridgeService_InvokeStreamServer) error {
+	ctx := stream.Context()
+	callers := newCallerIDs()
+	var sendMu sync.Mutex
+	send := func(resp *pb.InvokeResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(resp)
+	}
+
+	requests := make(chan *pb.InvokeRequest, framedQueueSize)
+	sendErr := make(chan error, 1)
+	var worker sync.WaitGroup
+	worker.Add(1)
+	go func() {
+		defer worker.Done()
+		for req := range requests {
+			if err := send(s.invokeMessage(ctx, req, callers)); err != nil {
+				sendErr <- err
+				for range requests {
+				}
+				return
+			}
+		}
+	}()
+
+	err := func() error {
+		for {
+			req, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if req.Type == "cancel" {
+				if err := send(s.invokeMessage(ctx, req, callers)); err != nil {
+					return err
+				}
+				continue
+			}
+			select {
+			case requests <- req:
+			case err := <-sendErr:
+				return err
+			}
+		}
+	}()
+
+	close(requests)
+	worker.Wait()
+	if err == nil {
+		select {
+		case err = <-sendErr:
+		default:
+		}
+	}
+	return err
+}
+
+// invokeMessage decodes a generic request's JSON payload, dispatches it and
+// JSON-encodes the handler's result. callers, if not nil, lets a cancel name a
+// request by the ID its caller gave it.
+func (s *grpcBridgeService) invokeMessage(ctx context.Context, req *pb.InvokeRequest, callers *callerIDs) *pb.InvokeResponse {
+	if _, exists := handlers.lookup(req.Type); !exists {
+		return &pb.InvokeResponse{
+			Id:      req.Id,
+			Success: false,
+			Error:   fmt.Sprintf("Unknown message type: %s", req.Type),
+			Code:    codeUnknownMessageType,
+		}
+	}
+
+	payload := map[string]interface{}{}
+	if req.Payload != "" {
+		if err := json.Unmarshal([]byte(req.Payload), &payload); err != nil {
+			return &pb.InvokeResponse{
+				Id:      req.Id,
+				Success: false,
+				Error:   fmt.Sprintf("Invalid JSON payload: %v", err),
+				Code:    codeWrongType,
+			}
+		}
+	}
+	if denied := checkMessageScope(ctx, req, payload); denied != nil {
+		return denied
+	}
+
+	resp := s.bridgeFor(ctx).callSyncAs(ctx, Message{
+		ID:         req.Id,
+		Type:       req.Type,
+		Payload:    payload,
+		DeadlineMs: req.DeadlineMs,
+	}, callers)
+
+	invokeResp := &pb.InvokeResponse{
+		Id:      req.Id,
+		Success: resp.Success,
+		Error:   resp.Error,
+		Code:    resp.Code,
+		Field:   resp.Field,
+	}
+
+	if resp.Result != nil {
+		result, err := json.Marshal(resp.Result)
+		if err != nil {
+			invokeResp.Success = false
+			invokeResp.Error = fmt.Sprintf("Failed to encode result: %v", err)
+			return invokeResp
+		}
+		invokeResp.Result = string(result)
+	}
+
+	return invokeResp
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	pb "github.com/paul-hammant/tsyne/bridge/proto"
+)
+
+const (
+	// sessionCommandBuffer is how many commands a client may pipeline ahead of
+	// the one currently executing before Recv stops reading from the stream
+	sessionCommandBuffer = 1024
+
+	// sessionOutboundBuffer is how many responses and events may queue for the sender
+	sessionOutboundBuffer = 1024
+)
+
+// grpcSession is one Session stream.
+// Commands are executed one at a time in the order received, so a client can
+// pipeline a whole widget tree without waiting for each response. A cancel is
+// executed as soon as it is read, and may name a command by its ID. Responses
+// and events share a single sender goroutine because a gRPC stream must not
+// be written concurrently.
+type grpcSession struct {
+	service  *grpcBridgeService
+	bridge   *Bridge // the session the stream was opened in
+	stream   pb.BridgeService_SessionServer
+	ctx      context.Context
+	commands chan *pb.InvokeRequest
+	outbound chan *pb.SessionResponse
+	callers  *callerIDs // caller IDs of the running commands
+
+	subID       int // current event bus subscription, 0 if none
+	subscribers sync.WaitGroup
+}
+
+// Session runs a bidirectional command/response/event stream
+func (s *grpcBridgeService) Session(stream pb.BridgeService_SessionServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	session := &grpcSession{
+		service:  s,
+		bridge:   s.bridgeFor(ctx),
+		stream:   stream,
+		ctx:      ctx,
+		commands: make(chan *pb.InvokeRequest, sessionCommandBuffer),
+		outbound: make(chan *pb.SessionResponse, sessionOutboundBuffer),
+		callers:  newCallerIDs(),
+	}
+	logGrpc.Infof("Session started")
+
+	sendErr := make(chan error, 1)
+	go func() {
+		err := session.sendLoop()
+		cancel()
+		// Keep draining so producers blocked on outbound can exit
+		for range session.outbound {
+		}
+		sendErr <- err
+	}()
+
+	var worker sync.WaitGroup
+	worker.Add(1)
+	go func() {
+		defer worker.Done()
+		session.runCommands()
+	}()
+
+	recvErr := session.recvLoop()
+
+	// Let pipelined commands finish and their responses drain before closing
+	close(session.commands)
+	worker.Wait()
+	session.unsubscribe()
+	session.subscribers.Wait()
+	close(session.outbound)
+
+	if err := <-sendErr; err != nil {
+		logGrpc.Infof("Session ended: %v", err)
+		return err
+	}
+	logGrpc.Infof("Session ended")
+	return recvErr
+}
+
+// recvLoop reads client messages until the client half-closes the stream or
+// the session is cancelled
+func (gs *grpcSession) recvLoop() error {
+	for {
+		req, err := gs.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch kind := req.Kind.(type) {
+		case *pb.SessionRequest_Command:
+			if kind.Command.GetType() == "cancel" {
+				// Not queued behind the command it cancels
+				gs.respond(kind.Command)
+				continue
+			}
+			select {
+			case gs.commands <- kind.Command:
+			case <-gs.ctx.Done():
+				return gs.ctx.Err()
+			}
+		case *pb.SessionRequest_Subscribe:
+			gs.subscribe(kind.Subscribe.GetEventTypes())
+		default:
+			logGrpc.Debugf("Session: ignoring empty request")
+		}
+	}
+}
+
+// runCommands executes commands in order and queues each response
+func (gs *grpcSession) runCommands() {
+	for req := range gs.commands {
+		if gs.ctx.Err() != nil {
+			continue // sender has gone, drain without executing
+		}
+		gs.respond(req)
+	}
+}
+
+// respond executes a command and queues its response
+func (gs *grpcSession) respond(req *pb.InvokeRequest) {
+	resp := gs.service.invokeMessage(gs.ctx, req, gs.callers)
+	gs.queue(&pb.SessionResponse{Kind: &pb.SessionResponse_Response{Response: resp}})
+}
+
+// subscribe replaces the session's event subscription
+func (gs *grpcSession) subscribe(eventTypes []string) {
+	gs.unsubscribe()
+
+	subID, events := gs.bridge.events.subscribe(eventTypes)
+	gs.subID = subID
+	logGrpc.Debugf("Session subscribed to events: %v", eventTypes)
+
+	gs.subscribers.Add(1)
+	go func() {
+		defer gs.subscribers.Done()
+		for event := range events {
+			gs.queue(&pb.SessionResponse{Kind: &pb.SessionResponse_Event{Event: toProtoEvent(event)}})
+		}
+	}()
+}
+
+// unsubscribe ends the current event subscription, if any
+func (gs *grpcSession) unsubscribe() {
+	if gs.subID != 0 {
+		gs.bridge.events.unsubscribe(gs.subID)
+		gs.subID = 0
+	}
+}
+
+// queue hands a message to the sender, giving up if the session is cancelled
+func (gs *grpcSession) queue(resp *pb.SessionResponse) {
+	select {
+	case gs.outbound <- resp:
+	case <-gs.ctx.Done():
+	}
+}
+
+// sendLoop is the only writer to the stream
+func (gs *grpcSession) sendLoop() error {
+	for resp := range gs.outbound {
+		if err := gs.stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
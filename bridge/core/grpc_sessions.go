@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/paul-hammant/tsyne/bridge/proto"
+)
+
+// sessionMetadataKey is the gRPC metadata naming the session a call is for.
+// Calls without it use the default session.
+const sessionMetadataKey = "session"
+
+// errSessionClosed ends a stream whose session was closed while it was open
+var errSessionClosed = status.Error(codes.NotFound, "session closed")
+
+// grpcSessionKey carries the Bridge of the caller's session in a request context
+type grpcSessionKey struct{}
+
+// sessionFor finds the Bridge of the session named in a call's metadata.
+// A session other than the default one may only be used with a token that
+// acts for the token that opened it.
+func (m *sessionManager) sessionFor(ctx context.Context) (*Bridge, error) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(sessionMetadataKey); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	b, exists := m.lookup(id)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "no session %s", id)
+	}
+	if !sessionAllowed(ctx, b) {
+		return nil, status.Errorf(codes.PermissionDenied, "session %s was opened with another token", id)
+	}
+	return b, nil
+}
+
+// sessionAllowed reports whether a call's token may use a session
+func sessionAllowed(ctx context.Context, b *Bridge) bool {
+	if b.session.owner == "" {
+		return true
+	}
+	token, ok := tokenFromContext(ctx)
+	return ok && token.actsFor(b.session.owner)
+}
+
+// sessionInterceptor routes each call to the session it names
+func sessionInterceptor(sessions *sessionManager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if tokenExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		b, err := sessions.sessionFor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, grpcSessionKey{}, b), req)
+	}
+}
+
+// sessionStreamInterceptor routes each stream to the session it names, and
+// ends the stream if the session is closed
+func sessionStreamInterceptor(sessions *sessionManager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if tokenExempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		b, err := sessions.sessionFor(ss.Context())
+		if err != nil {
+			return err
+		}
+		ctx := context.WithValue(ss.Context(), grpcSessionKey{}, b)
+		if b.session.id == defaultSessionID {
+			return handler(srv, &boundStream{ServerStream: ss, ctx: ctx})
+		}
+		bind := func(ctx context.Context) (context.Context, context.CancelFunc) {
+			return bindDone(ctx, b.session.done)
+		}
+		return serveBound(srv, ss, handler, ctx, bind, errSessionClosed)
+	}
+}
+
+// bridgeFor is the Bridge of the session a call was routed to
+func (s *grpcBridgeService) bridgeFor(ctx context.Context) *Bridge {
+	if b, ok := ctx.Value(grpcSessionKey{}).(*Bridge); ok {
+		return b
+	}
+	return s.bridge
+}
+
+// sessionInfo describes a session for ListSessions
+func sessionInfo(b *Bridge) *pb.SessionInfo {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return &pb.SessionInfo{
+		Id:        b.session.id,
+		Name:      b.session.name,
+		CreatedAt: b.session.created.Unix(),
+		Windows:   int32(len(b.windows)),
+		Widgets:   int32(len(b.widgets)),
+	}
+}
+
+// OpenSession starts a session with its own widgets, windows and events
+func (s *grpcBridgeService) OpenSession(ctx context.Context, req *pb.OpenSessionRequest) (*pb.SessionInfo, error) {
+	creator, _ := tokenFromContext(ctx)
+	b := s.bridge.sessions.open(req.Name, creator.id)
+	logGrpc.Infof("Opened session %s (%q) for %s", b.session.id, b.session.name, creator.id)
+	return sessionInfo(b), nil
+}
+
+// CloseSession closes a session's windows and ends the streams opened in it
+func (s *grpcBridgeService) CloseSession(ctx context.Context, req *pb.CloseSessionRequest) (*pb.Response, error) {
+	if b, exists := s.bridge.sessions.lookup(req.Id); exists && !sessionAllowed(ctx, b) {
+		return nil, status.Errorf(codes.PermissionDenied, "session %s was opened with another token", req.Id)
+	}
+	if err := s.bridge.sessions.close(req.Id); err != nil {
+		return &pb.Response{Success: false, Error: err.Error()}, nil
+	}
+	logGrpc.Infof("Closed session %s", req.Id)
+	return &pb.Response{Success: true}, nil
+}
+
+// ListSessions lists the open sessions the caller's token may use, the
+// default one first
+func (s *grpcBridgeService) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	resp := &pb.ListSessionsResponse{}
+	for _, b := range s.bridge.sessions.list() {
+		if !sessionAllowed(ctx, b) {
+			continue
+		}
+		resp.Sessions = append(resp.Sessions, sessionInfo(b))
+	}
+	return resp, nil
+}
@@ -0,0 +1,514 @@
+package core
+
+import "log"
+
+// builtinHandlers are the message types the bridge handles itself
+var builtinHandlers = []HandlerSpec{
+	{Type: "createWindow", Handler: (*Bridge).handleCreateWindow, MainThread: true, Payload: []FieldSchema{
+		Required("title", FieldString),
+		Required("id", FieldString),
+		Optional("width", FieldNumber),
+		Optional("height", FieldNumber),
+		Optional("fixedSize", FieldBool),
+		Optional("shared", FieldBool),
+	}},
+	{Type: "setContent", Handler: (*Bridge).handleSetContent, MainThread: true, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "clearWidgets", Handler: (*Bridge).handleClearWidgets},
+	{Type: "showWindow", Handler: (*Bridge).handleShowWindow, MainThread: true, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+	}},
+	{Type: "createButton", Handler: (*Bridge).handleCreateButton, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("text", FieldString),
+		Optional("callbackId", FieldString),
+		Optional("importance", FieldString),
+	}},
+	{Type: "createLabel", Handler: (*Bridge).handleCreateLabel, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("text", FieldString),
+	}},
+	{Type: "createEntry", Handler: (*Bridge).handleCreateEntry, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("placeholder", FieldString),
+		Optional("callbackId", FieldString),
+		Optional("minWidth", FieldNumber),
+		Optional("doubleClickCallbackId", FieldString),
+	}},
+	{Type: "createMultiLineEntry", Handler: (*Bridge).handleCreateMultiLineEntry, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("placeholder", FieldString),
+		Optional("wrapping", FieldString),
+	}},
+	{Type: "createPasswordEntry", Handler: (*Bridge).handleCreatePasswordEntry, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("placeholder", FieldString),
+		Optional("callbackId", FieldString),
+	}},
+	{Type: "createSeparator", Handler: (*Bridge).handleCreateSeparator, Payload: []FieldSchema{
+		Required("id", FieldString),
+	}},
+	{Type: "createHyperlink", Handler: (*Bridge).handleCreateHyperlink, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("text", FieldString),
+		Required("url", FieldString),
+	}},
+	{Type: "createVBox", Handler: (*Bridge).handleCreateVBox, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("children", FieldArray),
+	}},
+	{Type: "createHBox", Handler: (*Bridge).handleCreateHBox, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("children", FieldArray),
+	}},
+	{Type: "createCheckbox", Handler: (*Bridge).handleCreateCheckbox, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("text", FieldString),
+		Optional("callbackId", FieldString),
+	}},
+	{Type: "createSelect", Handler: (*Bridge).handleCreateSelect, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("options", FieldArray),
+		Optional("callbackId", FieldString),
+	}},
+	{Type: "createSlider", Handler: (*Bridge).handleCreateSlider, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("min", FieldNumber),
+		Required("max", FieldNumber),
+		Optional("callbackId", FieldString),
+		Optional("value", FieldNumber),
+	}},
+	{Type: "createProgressBar", Handler: (*Bridge).handleCreateProgressBar, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("infinite", FieldBool),
+		Optional("value", FieldNumber),
+	}},
+	{Type: "createScroll", Handler: (*Bridge).handleCreateScroll, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("contentId", FieldString),
+	}},
+	{Type: "createGrid", Handler: (*Bridge).handleCreateGrid, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("children", FieldArray),
+		Required("columns", FieldNumber),
+	}},
+	{Type: "createCenter", Handler: (*Bridge).handleCreateCenter, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("childId", FieldString),
+	}},
+	{Type: "createMax", Handler: (*Bridge).handleCreateMax, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("childIds", FieldArray),
+	}},
+	{Type: "createCard", Handler: (*Bridge).handleCreateCard, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("title", FieldString),
+		Optional("subtitle", FieldString),
+		Required("contentId", FieldString),
+	}},
+	{Type: "createAccordion", Handler: (*Bridge).handleCreateAccordion, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("items", FieldArray),
+	}},
+	{Type: "createForm", Handler: (*Bridge).handleCreateForm, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("items", FieldArray),
+		Optional("submitCallbackId", FieldString),
+		Optional("cancelCallbackId", FieldString),
+	}},
+	{Type: "createTree", Handler: (*Bridge).handleCreateTree, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("rootLabel", FieldString),
+	}},
+	{Type: "createRichText", Handler: (*Bridge).handleCreateRichText, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("segments", FieldArray),
+	}},
+	{Type: "createImage", Handler: (*Bridge).handleCreateImage, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("path", FieldString),
+		Optional("resource", FieldString),
+		Optional("fillMode", FieldString),
+		Optional("onDragCallbackId", FieldString),
+		Optional("onDragEndCallbackId", FieldString),
+		Optional("callbackId", FieldString),
+	}},
+	{Type: "updateImage", Handler: (*Bridge).handleUpdateImage, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Optional("path", FieldString),
+		Optional("resource", FieldString),
+		Optional("svg", FieldString),
+		Optional("url", FieldString),
+		Optional("imageData", FieldBytes),
+	}},
+	{Type: "registerResource", Handler: (*Bridge).handleRegisterResource, Payload: []FieldSchema{
+		Required("name", FieldString),
+		Required("data", FieldBytes),
+	}},
+	{Type: "unregisterResource", Handler: (*Bridge).handleUnregisterResource, Payload: []FieldSchema{
+		Required("name", FieldString),
+	}},
+	{Type: "createBorder", Handler: (*Bridge).handleCreateBorder, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("topId", FieldString),
+		Optional("bottomId", FieldString),
+		Optional("leftId", FieldString),
+		Optional("rightId", FieldString),
+		Optional("centerId", FieldString),
+	}},
+	{Type: "createGridWrap", Handler: (*Bridge).handleCreateGridWrap, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Optional("children", FieldArray),
+		Required("itemWidth", FieldNumber),
+		Required("itemHeight", FieldNumber),
+	}},
+	{Type: "createRadioGroup", Handler: (*Bridge).handleCreateRadioGroup, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("options", FieldArray),
+		Optional("callbackId", FieldString),
+		Optional("selected", FieldString),
+	}},
+	{Type: "createSplit", Handler: (*Bridge).handleCreateSplit, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("orientation", FieldString),
+		Required("leadingId", FieldString),
+		Required("trailingId", FieldString),
+		Optional("offset", FieldNumber),
+	}},
+	{Type: "createTabs", Handler: (*Bridge).handleCreateTabs, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("tabs", FieldArray),
+		Optional("location", FieldString),
+	}},
+	{Type: "setText", Handler: (*Bridge).handleSetText, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("text", FieldString),
+	}},
+	{Type: "getText", Handler: (*Bridge).handleGetText, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "setProgress", Handler: (*Bridge).handleSetProgress, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("value", FieldNumber),
+	}},
+	{Type: "getProgress", Handler: (*Bridge).handleGetProgress, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "setChecked", Handler: (*Bridge).handleSetChecked, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("checked", FieldBool),
+	}},
+	{Type: "getChecked", Handler: (*Bridge).handleGetChecked, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "setSelected", Handler: (*Bridge).handleSetSelected, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("selected", FieldString),
+	}},
+	{Type: "getSelected", Handler: (*Bridge).handleGetSelected, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "setValue", Handler: (*Bridge).handleSetValue, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("value", FieldNumber),
+	}},
+	{Type: "getValue", Handler: (*Bridge).handleGetValue, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "setRadioSelected", Handler: (*Bridge).handleSetRadioSelected, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("selected", FieldString),
+	}},
+	{Type: "getRadioSelected", Handler: (*Bridge).handleGetRadioSelected, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "showInfo", Handler: (*Bridge).handleShowInfo, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("title", FieldString),
+		Required("message", FieldString),
+	}},
+	{Type: "showError", Handler: (*Bridge).handleShowError, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("title", FieldString),
+		Required("message", FieldString),
+	}},
+	{Type: "showConfirm", Handler: (*Bridge).handleShowConfirm, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("title", FieldString),
+		Required("message", FieldString),
+		Required("callbackId", FieldString),
+	}},
+	{Type: "showFileOpen", Handler: (*Bridge).handleShowFileOpen, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("callbackId", FieldString),
+	}},
+	{Type: "showFileSave", Handler: (*Bridge).handleShowFileSave, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("callbackId", FieldString),
+		Optional("fileName", FieldString),
+	}},
+	{Type: "showCustom", Handler: (*Bridge).handleShowCustom, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("title", FieldString),
+		Required("contentId", FieldString),
+		Optional("dismissText", FieldString),
+		Optional("callbackId", FieldString),
+	}},
+	{Type: "showCustomConfirm", Handler: (*Bridge).handleShowCustomConfirm, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("title", FieldString),
+		Required("contentId", FieldString),
+		Optional("confirmText", FieldString),
+		Optional("dismissText", FieldString),
+		Required("callbackId", FieldString),
+	}},
+	{Type: "resizeWindow", Handler: (*Bridge).handleResizeWindow, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("width", FieldNumber),
+		Required("height", FieldNumber),
+	}},
+	{Type: "setWindowTitle", Handler: (*Bridge).handleSetWindowTitle, MainThread: true, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("title", FieldString),
+	}},
+	{Type: "centerWindow", Handler: (*Bridge).handleCenterWindow, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+	}},
+	{Type: "setWindowFullScreen", Handler: (*Bridge).handleSetWindowFullScreen, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("fullscreen", FieldBool),
+	}},
+	{Type: "setMainMenu", Handler: (*Bridge).handleSetMainMenu, MainThread: true, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("menuItems", FieldArray),
+	}},
+	{Type: "createToolbar", Handler: (*Bridge).handleCreateToolbar, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("items", FieldArray),
+	}},
+	{Type: "createTable", Handler: (*Bridge).handleCreateTable, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("headers", FieldArray),
+		Required("data", FieldArray),
+	}},
+	{Type: "createList", Handler: (*Bridge).handleCreateList, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("items", FieldArray),
+		Optional("callbackId", FieldString),
+	}},
+	{Type: "updateTableData", Handler: (*Bridge).handleUpdateTableData, MainThread: true, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("data", FieldArray),
+	}},
+	{Type: "updateListData", Handler: (*Bridge).handleUpdateListData, MainThread: true, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("items", FieldArray),
+	}},
+	{Type: "getTableData", Handler: (*Bridge).handleGetTableData, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("id", FieldString),
+	}},
+	{Type: "getListData", Handler: (*Bridge).handleGetListData, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("id", FieldString),
+	}},
+	{Type: "getToolbarItems", Handler: (*Bridge).handleGetToolbarItems, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "getContainerObjects", Handler: (*Bridge).handleGetContainerObjects, MainThread: true, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldContainer),
+	}},
+	{Type: "setTheme", Handler: (*Bridge).handleSetTheme, Payload: []FieldSchema{
+		Required("theme", FieldString),
+	}},
+	{Type: "getTheme", Handler: (*Bridge).handleGetTheme, Scope: ScopeRead},
+	{Type: "setFontScale", Handler: (*Bridge).handleSetFontScale, MainThread: true, Payload: []FieldSchema{
+		Optional("scale", FieldNumber),
+	}},
+	{Type: "setWidgetStyle", Handler: (*Bridge).handleSetWidgetStyle, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Optional("fontStyle", FieldString),
+		Optional("fontFamily", FieldString),
+		Optional("textAlign", FieldString),
+		Optional("fontSize", FieldNumber),
+		Optional("backgroundColor", FieldString),
+	}},
+	{Type: "setWidgetContextMenu", Handler: (*Bridge).handleSetWidgetContextMenu, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("items", FieldArray),
+	}},
+	{Type: "quit", Handler: (*Bridge).handleQuit, MainThread: true},
+	// Testing methods
+	{Type: "findWidget", Handler: (*Bridge).handleFindWidget, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("selector", FieldString),
+		Required("type", FieldString),
+	}},
+	{Type: "clickWidget", Handler: (*Bridge).handleClickWidget, MainThread: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "clickToolbarAction", Handler: (*Bridge).handleClickToolbarAction, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("customId", FieldString),
+	}},
+	{Type: "typeText", Handler: (*Bridge).handleTypeText, MainThread: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("text", FieldString),
+	}},
+	{Type: "getWidgetInfo", Handler: (*Bridge).handleGetWidgetInfo, MainThread: true, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "getAllWidgets", Handler: (*Bridge).handleGetAllWidgets, MainThread: true, Scope: ScopeRead},
+	{Type: "captureWindow", Handler: (*Bridge).handleCaptureWindow, MainThread: true, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("filePath", FieldString),
+	}},
+	{Type: "doubleTapWidget", Handler: (*Bridge).handleDoubleTapWidget, MainThread: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "rightClickWidget", Handler: (*Bridge).handleRightClickWidget, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "dragWidget", Handler: (*Bridge).handleDragWidget, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("x", FieldNumber),
+		Required("y", FieldNumber),
+	}},
+	{Type: "hoverWidget", Handler: (*Bridge).handleHoverWidget, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Required("windowId", FieldWindow),
+	}},
+	{Type: "scrollCanvas", Handler: (*Bridge).handleScrollCanvas, TestOnly: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("deltaX", FieldNumber),
+		Required("deltaY", FieldNumber),
+	}},
+	{Type: "dragCanvas", Handler: (*Bridge).handleDragCanvas, TestOnly: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+		Required("fromX", FieldNumber),
+		Required("fromY", FieldNumber),
+		Required("deltaX", FieldNumber),
+		Required("deltaY", FieldNumber),
+	}},
+	{Type: "focusNext", Handler: (*Bridge).handleFocusNext, TestOnly: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+	}},
+	{Type: "focusPrevious", Handler: (*Bridge).handleFocusPrevious, TestOnly: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("windowId", FieldWindow),
+	}},
+	{Type: "containerAdd", Handler: (*Bridge).handleContainerAdd, MainThread: true, Payload: []FieldSchema{
+		Required("containerId", FieldContainer),
+		Required("childId", FieldWidget),
+	}},
+	{Type: "containerRemoveAll", Handler: (*Bridge).handleContainerRemoveAll, MainThread: true, Payload: []FieldSchema{
+		Required("containerId", FieldContainer),
+	}},
+	{Type: "containerRefresh", Handler: (*Bridge).handleContainerRefresh, MainThread: true, Payload: []FieldSchema{
+		Required("containerId", FieldContainer),
+	}},
+	{Type: "disableWidget", Handler: (*Bridge).handleDisableWidget, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "enableWidget", Handler: (*Bridge).handleEnableWidget, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "isEnabled", Handler: (*Bridge).handleIsEnabled, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "focusWidget", Handler: (*Bridge).handleFocusWidget, MainThread: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "submitEntry", Handler: (*Bridge).handleSubmitEntry, MainThread: true, Scope: ScopeInteract, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "hideWidget", Handler: (*Bridge).handleHideWidget, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "showWidget", Handler: (*Bridge).handleShowWidget, MainThread: true, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "registerCustomId", Handler: (*Bridge).handleRegisterCustomId, Payload: []FieldSchema{
+		Required("widgetId", FieldString),
+		Required("customId", FieldString),
+	}},
+	{Type: "getParent", Handler: (*Bridge).handleGetParent, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("widgetId", FieldString),
+	}},
+	{Type: "setAccessibility", Handler: (*Bridge).handleSetAccessibility, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Optional("label", FieldString),
+		Optional("description", FieldString),
+		Optional("role", FieldString),
+		Optional("hint", FieldString),
+	}},
+	{Type: "enableAccessibility", Handler: (*Bridge).handleEnableAccessibility},
+	{Type: "disableAccessibility", Handler: (*Bridge).handleDisableAccessibility},
+	{Type: "hello", Handler: (*Bridge).handleHello, Scope: ScopeRead, Payload: []FieldSchema{
+		Required("protocolVersion", FieldString),
+		Optional("encodings", FieldArray),
+	}},
+	{Type: "uploadBegin", Handler: (*Bridge).handleUploadBegin, Payload: []FieldSchema{
+		Required("target", FieldString),
+		Required("name", FieldString),
+		Optional("sha256", FieldString),
+		Optional("uploadId", FieldString),
+	}},
+	{Type: "uploadAppend", Handler: (*Bridge).handleUploadAppend, Payload: []FieldSchema{
+		Required("uploadId", FieldString),
+		Required("index", FieldNumber),
+		Required("crc32", FieldNumber),
+		Required("data", FieldBytes),
+	}},
+	{Type: "uploadCommit", Handler: (*Bridge).handleUploadCommit, Payload: []FieldSchema{
+		Required("uploadId", FieldString),
+		Optional("sha256", FieldString),
+	}},
+	{Type: "uploadAbort", Handler: (*Bridge).handleUploadAbort, Payload: []FieldSchema{
+		Required("uploadId", FieldString),
+	}},
+	{Type: "announce", Handler: (*Bridge).handleAnnounce, Payload: []FieldSchema{
+		Optional("text", FieldString),
+	}},
+	{Type: "stopSpeech", Handler: (*Bridge).handleStopSpeech},
+	{Type: "setPointerEnter", Handler: (*Bridge).handleSetPointerEnter, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+	}},
+	{Type: "processHoverWrappers", Handler: (*Bridge).handleProcessHoverWrappers},
+	{Type: "setWidgetHoverable", Handler: (*Bridge).handleSetWidgetHoverable, Payload: []FieldSchema{
+		Required("widgetId", FieldWidget),
+		Optional("onMouseInCallbackId", FieldString),
+		Optional("onMouseMoveCallbackId", FieldString),
+		Optional("onMouseOutCallbackId", FieldString),
+		Optional("onMouseDownCallbackId", FieldString),
+		Optional("onMouseUpCallbackId", FieldString),
+		Optional("onKeyDownCallbackId", FieldString),
+		Optional("onKeyUpCallbackId", FieldString),
+		Optional("onFocusCallbackId", FieldString),
+		Optional("cursorType", FieldString),
+	}},
+	{Type: "createMenu", Handler: (*Bridge).handleCreateMenu, Payload: []FieldSchema{
+		Required("id", FieldString),
+		Required("items", FieldArray),
+	}},
+	{Type: "batch", Handler: (*Bridge).handleBatch, Payload: []FieldSchema{
+		Required("messages", FieldArray),
+	}},
+	{Type: "describe", Handler: (*Bridge).handleDescribe, Scope: ScopeRead, Payload: []FieldSchema{
+		Optional("type", FieldString),
+	}},
+	{Type: "ping", Handler: (*Bridge).handlePing, Scope: ScopeRead},
+	{Type: "cancel", Handler: (*Bridge).handleCancel, Payload: []FieldSchema{
+		Required("requestId", FieldString),
+	}},
+	{Type: "setLogLevel", Handler: (*Bridge).handleSetLogLevel, Payload: []FieldSchema{
+		Optional("level", FieldString),
+		Optional("category", FieldString),
+		Optional("spec", FieldString),
+	}},
+}
+
+func init() {
+	for _, spec := range builtinHandlers {
+		if err := handlers.register(spec, false); err != nil {
+			log.Fatalf("[registry] %v", err)
+		}
+	}
+}
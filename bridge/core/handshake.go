@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// protocolVersion is the bridge protocol spoken by this binary, as "major.minor".
+// Bump the minor version when adding message types or fields, and the major
+// version when changing or removing existing ones. The Go client's
+// ProtocolVersion (client/client.go) and the TypeScript PROTOCOL_VERSION
+// (src/fynebridge.ts) follow this.
+const protocolVersion = "2.9"
+
+// parseProtocolVersion splits a "major.minor" version string
+func parseProtocolVersion(version string) (major, minor int, err error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unknown protocol version %q, expected major.minor", version)
+	}
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown protocol version %q, expected major.minor", version)
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown protocol version %q, expected major.minor", version)
+	}
+	return major, minor, nil
+}
+
+// CheckProtocolVersion reports whether a client speaking the given version can
+// talk to this bridge. The major versions must match, and the client must not
+// expect a newer minor version than the bridge provides.
+func CheckProtocolVersion(clientVersion string) error {
+	clientMajor, clientMinor, err := parseProtocolVersion(clientVersion)
+	if err != nil {
+		return err
+	}
+	bridgeMajor, bridgeMinor, _ := parseProtocolVersion(protocolVersion)
+
+	if clientMajor != bridgeMajor || clientMinor > bridgeMinor {
+		return fmt.Errorf("incompatible protocol version: client speaks %s, bridge speaks %s",
+			clientVersion, protocolVersion)
+	}
+	return nil
+}
+
+// fyneVersion reports the Fyne module version the bridge was built with
+func fyneVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "fyne.io/fyne/v2" {
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// capabilities describes what this bridge supports, for the ready response,
+// the gRPC connection info and the hello message
+func (b *Bridge) capabilities() map[string]interface{} {
+	return map[string]interface{}{
+		"protocolVersion": protocolVersion,
+		"fyneVersion":     fyneVersion(),
+		"messageTypes":    handlers.types(),
+		"encodings":       supportedEncodings,
+		"features": map[string]interface{}{
+			"grpc":      true,
+			"websocket": b.websocketInfo != nil,
+			"testMode":  b.testMode,
+			// Fyne has no native accessibility API yet; accessibility info is
+			// reported to the client as events
+			"accessibilityBackends": []string{"events"},
+		},
+	}
+}
+
+// handleHello checks the protocol version declared by the client and replies
+// with the bridge's capabilities. If the client lists the encodings it
+// accepts, the bridge picks one and uses it for every frame after the reply.
+// Only the default Responder's connection (stdio or socket) can switch
+// encoding; a hello routed from any other transport ignores encodings, so
+// it cannot change the framing of a connection it did not come in on.
+func (b *Bridge) handleHello(msg Message) {
+	clientVersion, ok := msg.Payload["protocolVersion"].(string)
+	if !ok {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "hello requires protocolVersion",
+		})
+		return
+	}
+
+	if err := CheckProtocolVersion(clientVersion); err != nil {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	result := b.capabilities()
+	preferred, negotiating := msg.Payload["encodings"].([]interface{})
+	if negotiating && b.routes.has(msg.ID) {
+		logProtocol.Debugf("Ignoring encodings in hello %s from a routed transport", msg.ID)
+		negotiating = false
+	}
+	encoding := negotiateEncoding(preferred)
+	if negotiating {
+		result["encoding"] = encoding
+	}
+
+	// The reply goes out in the current encoding, so the client can read it
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result:  result,
+	})
+
+	if negotiating {
+		// Frames still queued would otherwise be written in the new encoding
+		b.outbound.flush()
+		if switcher, ok := b.defaultResponder().(interface{ SetEncoding(string) }); ok {
+			logProtocol.Infof("Switching to %s encoding", encoding)
+			switcher.SetEncoding(encoding)
+		}
+	}
+}
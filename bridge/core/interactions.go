@@ -1,8 +1,7 @@
-package main
+package core
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -49,7 +48,7 @@ func (b *Bridge) handleClickWidget(msg Message) {
 			var callback func(bool)
 
 			// Update UI on main thread
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				check.SetChecked(newState)
 				// Capture callback reference (don't call it here - would block main thread)
 				callback = check.OnChanged
@@ -87,7 +86,7 @@ func (b *Bridge) handleClickWidget(msg Message) {
 				test.Tap(hyperlink)
 			} else {
 				// Trigger hyperlink tap on main thread
-				fyne.DoAndWait(func() {
+				b.runOnMain(msg.Context(), func() {
 					hyperlink.Tapped(&fyne.PointEvent{})
 				})
 			}
@@ -103,7 +102,7 @@ func (b *Bridge) handleClickWidget(msg Message) {
 			test.Tap(draggable)
 		} else {
 			// Trigger tap on main thread
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				draggable.Tapped(&fyne.PointEvent{})
 			})
 		}
@@ -117,7 +116,7 @@ func (b *Bridge) handleClickWidget(msg Message) {
 			test.Tap(clickable)
 		} else {
 			// Trigger tap on main thread
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				clickable.Tapped(&fyne.PointEvent{})
 			})
 		}
@@ -134,7 +133,7 @@ func (b *Bridge) handleClickWidget(msg Message) {
 				test.Tap(tappable)
 			} else {
 				// Trigger tap on main thread
-				fyne.DoAndWait(func() {
+				b.runOnMain(msg.Context(), func() {
 					tappable.Tapped(&fyne.PointEvent{})
 				})
 			}
@@ -333,7 +332,7 @@ func (b *Bridge) handleHoverWidget(msg Message) {
 	b.mu.RLock()
 	obj, exists := b.widgets[widgetID]
 	windowID := msg.Payload["windowId"].(string)
-	win, winExists := b.windows[windowID]
+	win, winExists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -415,7 +414,7 @@ func (b *Bridge) handleTypeText(msg Message) {
 			test.Type(entry, text)
 		} else {
 			// UI operations must be called on the main thread
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				entry.SetText(text)
 			})
 		}
@@ -464,7 +463,7 @@ func (b *Bridge) handleSubmitEntry(msg Message) {
 		if entry, ok := entryObj.(*widget.Entry); ok {
 			if entry.OnSubmitted != nil {
 				// Trigger the OnSubmitted callback
-				fyne.DoAndWait(func() {
+				b.runOnMain(msg.Context(), func() {
 					entry.OnSubmitted(entry.Text)
 				})
 				b.sendResponse(Response{
@@ -480,7 +479,7 @@ func (b *Bridge) handleSubmitEntry(msg Message) {
 	if entry, ok := obj.(*widget.Entry); ok {
 		if entry.OnSubmitted != nil {
 			// Trigger the OnSubmitted callback
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				entry.OnSubmitted(entry.Text)
 			})
 			b.sendResponse(Response{
@@ -506,7 +505,7 @@ func (b *Bridge) handleDragCanvas(msg Message) {
 	deltaY := msg.Payload["deltaY"].(float64)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -541,7 +540,7 @@ func (b *Bridge) handleScrollCanvas(msg Message) {
 	deltaY := msg.Payload["deltaY"].(float64)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -597,7 +596,7 @@ func (b *Bridge) handleFocusWidget(msg Message) {
 			for _, win := range b.windows {
 				canvas := win.Canvas()
 				if canvas != nil {
-					fyne.DoAndWait(func() {
+					b.runOnMain(msg.Context(), func() {
 						canvas.Focus(entry)
 					})
 					b.sendResponse(Response{
@@ -616,7 +615,7 @@ func (b *Bridge) handleFocusWidget(msg Message) {
 		for _, win := range b.windows {
 			canvas := win.Canvas()
 			if canvas != nil {
-				fyne.DoAndWait(func() {
+				b.runOnMain(msg.Context(), func() {
 					canvas.Focus(focusable)
 				})
 				b.sendResponse(Response{
@@ -644,7 +643,7 @@ func (b *Bridge) handleFocusNext(msg Message) {
 	windowID := msg.Payload["windowId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -676,7 +675,7 @@ func (b *Bridge) handleFocusPrevious(msg Message) {
 	windowID := msg.Payload["windowId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -828,20 +827,25 @@ func (b *Bridge) handleGetWidgetInfo(msg Message) {
 	}
 
 	// Get current widget properties - must happen on main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		pos := obj.Position()
 		size := obj.Size()
 
 		// Get absolute position
 		absPos := b.app.Driver().AbsolutePositionForObject(obj)
 
-		log.Printf("[DEBUG] handleGetWidgetInfo: widgetId=%s, pos=(%f, %f), absPos=(%f, %f), size=(%f, %f)", widgetID, pos.X, pos.Y, absPos.X, absPos.Y, size.Width, size.Height)
+		logWidgets.Debugf("handleGetWidgetInfo: widgetId=%s, pos=(%f, %f), absPos=(%f, %f), size=(%f, %f)", widgetID, pos.X, pos.Y, absPos.X, absPos.Y, size.Width, size.Height)
 		info["x"] = pos.X
 		info["y"] = pos.Y
 		info["absoluteX"] = absPos.X
 		info["absoluteY"] = absPos.Y
 		info["width"] = size.Width
 		info["height"] = size.Height
+		info["visible"] = obj.Visible()
+		info["enabled"] = true
+		if disableable, ok := obj.(fyne.Disableable); ok {
+			info["enabled"] = !disableable.Disabled()
+		}
 
 		switch w := obj.(type) {
 		case *widget.Label:
@@ -909,7 +913,7 @@ func (b *Bridge) handleGetAllWidgets(msg Message) {
 	// Now access widget properties on the main thread
 	widgets := make([]map[string]interface{}, 0, len(widgetList))
 
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		for _, wd := range widgetList {
 			widgetInfo := map[string]interface{}{
 				"id":   wd.id,
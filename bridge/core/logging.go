@@ -0,0 +1,362 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logLevel orders log lines by importance
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+	levelOff
+)
+
+var logLevelNames = map[logLevel]string{
+	levelDebug: "debug",
+	levelInfo:  "info",
+	levelWarn:  "warn",
+	levelError: "error",
+	levelOff:   "off",
+}
+
+func parseLogLevel(name string) (logLevel, error) {
+	for level, levelName := range logLevelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn, error or off", name)
+}
+
+// Log categories, so one subsystem can be made verbose without the others
+var (
+	logBridge    = newCategoryLogger("bridge")    // startup, watchdog, crashes, recording
+	logProtocol  = newCategoryLogger("protocol")  // framing, encodings, requests, uploads
+	logWidgets   = newCategoryLogger("widgets")   // widget creation and updates
+	logEvents    = newCategoryLogger("events")    // pointer, keyboard and focus events sent to the client
+	logA11y      = newCategoryLogger("a11y")      // accessibility announcements
+	logGrpc      = newCategoryLogger("grpc")      // gRPC server and calls
+	logSocket    = newCategoryLogger("socket")    // socket mode connections
+	logWebSocket = newCategoryLogger("websocket") // WebSocket connections
+)
+
+// logCategories lists every category by name
+var logCategories = map[string]*categoryLogger{}
+
+// logConfig is where log lines go and how they look
+var logConfig = struct {
+	mu           sync.Mutex
+	out          io.Writer
+	json         bool
+	defaultLevel logLevel
+}{out: os.Stderr, defaultLevel: levelInfo}
+
+// categoryLogger writes the log lines of one category at or above its level
+type categoryLogger struct {
+	name     string
+	mu       sync.RWMutex
+	level    logLevel
+	override bool // level was set for this category rather than inherited
+}
+
+func newCategoryLogger(name string) *categoryLogger {
+	logger := &categoryLogger{name: name, level: levelInfo}
+	logCategories[name] = logger
+	return logger
+}
+
+func (l *categoryLogger) Debugf(format string, args ...interface{}) {
+	l.logf(levelDebug, format, args...)
+}
+
+func (l *categoryLogger) Infof(format string, args ...interface{}) {
+	l.logf(levelInfo, format, args...)
+}
+
+func (l *categoryLogger) Warnf(format string, args ...interface{}) {
+	l.logf(levelWarn, format, args...)
+}
+
+func (l *categoryLogger) Errorf(format string, args ...interface{}) {
+	l.logf(levelError, format, args...)
+}
+
+func (l *categoryLogger) enabled(level logLevel) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return level >= l.level && l.level != levelOff
+}
+
+func (l *categoryLogger) logf(level logLevel, format string, args ...interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	source := "???"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		source = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	writeLogLine(time.Now(), level, l.name, source, message)
+}
+
+// writeLogLine formats one line as text in the style of the standard logger,
+// or as a JSON object
+func writeLogLine(now time.Time, level logLevel, category, source, message string) {
+	logConfig.mu.Lock()
+	defer logConfig.mu.Unlock()
+
+	var line []byte
+	if logConfig.json {
+		line, _ = json.Marshal(map[string]string{
+			"time":     now.Format(time.RFC3339Nano),
+			"level":    logLevelNames[level],
+			"category": category,
+			"source":   source,
+			"msg":      message,
+		})
+	} else {
+		line = []byte(fmt.Sprintf("[tsyne-bridge] %s %s: %s [%s] %s",
+			now.Format("2006/01/02 15:04:05"), source, strings.ToUpper(logLevelNames[level]), category,
+			strings.TrimSuffix(message, "\n")))
+	}
+	logConfig.out.Write(append(line, '\n'))
+}
+
+// setLogLevels applies a level spec such as "info" or "warn,widgets=debug".
+// A bare level is the default for every category not named; an empty category
+// level (e.g. "widgets=") makes it follow the default again.
+func setLogLevels(spec string) error {
+	defaultLevel, hasDefault := logLevel(0), false
+	overrides := map[*categoryLogger]*logLevel{}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, isCategory := strings.Cut(part, "=")
+		if !isCategory {
+			level, err := parseLogLevel(part)
+			if err != nil {
+				return err
+			}
+			defaultLevel, hasDefault = level, true
+			continue
+		}
+
+		logger, exists := logCategories[strings.TrimSpace(name)]
+		if !exists {
+			return fmt.Errorf("unknown log category %q, expected one of %s", name, strings.Join(logCategoryNames(), ", "))
+		}
+		if strings.TrimSpace(value) == "" {
+			overrides[logger] = nil
+			continue
+		}
+		level, err := parseLogLevel(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		overrides[logger] = &level
+	}
+
+	logConfig.mu.Lock()
+	if hasDefault {
+		logConfig.defaultLevel = defaultLevel
+	}
+	defaultLevel = logConfig.defaultLevel
+	logConfig.mu.Unlock()
+
+	for _, logger := range logCategories {
+		logger.mu.Lock()
+		if level, named := overrides[logger]; named {
+			logger.override = level != nil
+			if level != nil {
+				logger.level = *level
+			}
+		}
+		if !logger.override {
+			logger.level = defaultLevel
+		}
+		logger.mu.Unlock()
+	}
+	return nil
+}
+
+// logLevels describes the current levels as a spec setLogLevels accepts
+func logLevels() string {
+	logConfig.mu.Lock()
+	parts := []string{logLevelNames[logConfig.defaultLevel]}
+	logConfig.mu.Unlock()
+
+	for _, name := range logCategoryNames() {
+		logger := logCategories[name]
+		logger.mu.RLock()
+		if logger.override {
+			parts = append(parts, name+"="+logLevelNames[logger.level])
+		}
+		logger.mu.RUnlock()
+	}
+	return strings.Join(parts, ",")
+}
+
+func logCategoryNames() []string {
+	names := make([]string, 0, len(logCategories))
+	for name := range logCategories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoggingOptions configures logging from flags and the environment
+type LoggingOptions struct {
+	Levels      string // level spec such as "warn,widgets=debug", see setLogLevels
+	Format      string // text or json
+	File        string // also write to this file, rotating it
+	FileMaxSize int64  // bytes before the file is rotated
+	FileBackups int    // rotated files to keep
+}
+
+// ConfigureLogging sets up logging before the bridge starts. It returns the
+// writer the standard logger should use, so lines from log.Fatalf and
+// third-party packages go to the same places.
+func ConfigureLogging(opts LoggingOptions) (io.Writer, error) {
+	if err := setLogLevels(opts.Levels); err != nil {
+		return nil, err
+	}
+
+	var out io.Writer = os.Stderr
+	if opts.File != "" {
+		file, err := openRotatingFile(opts.File, opts.FileMaxSize, opts.FileBackups)
+		if err != nil {
+			return nil, err
+		}
+		out = io.MultiWriter(os.Stderr, file)
+	}
+
+	logConfig.mu.Lock()
+	defer logConfig.mu.Unlock()
+	switch strings.ToLower(opts.Format) {
+	case "", "text":
+		logConfig.json = false
+	case "json":
+		logConfig.json = true
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected text or json", opts.Format)
+	}
+	logConfig.out = out
+	return out, nil
+}
+
+// rotatingFile is a log file that is renamed to path.1 (path.1 to path.2, and
+// so on) once it reaches its maximum size
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+func openRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	if r.backups > 0 {
+		for i := r.backups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		os.Rename(r.path, r.path+".1")
+	} else {
+		os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// handleSetLogLevel changes log levels while the bridge runs. The payload has
+// a level and optionally the category it applies to, or a full spec.
+func (b *Bridge) handleSetLogLevel(msg Message) {
+	spec, hasSpec := msg.Payload["spec"].(string)
+	level, hasLevel := msg.Payload["level"].(string)
+	if !hasSpec && !hasLevel {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "setLogLevel requires a level or a spec",
+			Code:    codeMissingField,
+			Field:   "level",
+		})
+		return
+	}
+	if hasLevel {
+		if category, hasCategory := msg.Payload["category"].(string); hasCategory && category != "" {
+			spec = category + "=" + level
+		} else {
+			spec = level
+		}
+	}
+
+	if err := setLogLevels(spec); err != nil {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logBridge.Infof("Log levels set to %s", logLevels())
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result:  map[string]interface{}{"levels": logLevels()},
+	})
+}
@@ -1,4 +1,4 @@
-package main
+package core
 
 import (
 	"fmt"
@@ -12,7 +12,7 @@ func (b *Bridge) handleSetMainMenu(msg Message) {
 	menuItemsInterface := msg.Payload["menuItems"].([]interface{})
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -45,6 +45,7 @@ func (b *Bridge) handleSetMainMenu(msg Message) {
 			// Regular menu item with callback
 			if callbackID, ok := itemData["callbackId"].(string); ok {
 				menuItem := fyne.NewMenuItem(itemLabel, func() {
+					defer b.recoverCallback("setMainMenu")
 					b.sendEvent(Event{
 						Type: "callback",
 						Data: map[string]interface{}{
@@ -72,7 +73,7 @@ func (b *Bridge) handleSetMainMenu(msg Message) {
 
 	mainMenu := fyne.NewMainMenu(menus...)
 	// Setting window menu must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		win.SetMainMenu(mainMenu)
 	})
 
@@ -152,6 +153,7 @@ func (b *Bridge) handleSetWidgetContextMenu(msg Message) {
 
 		menuItem := fyne.NewMenuItem(label, func(cid string) func() {
 			return func() {
+				defer b.recoverCallback("setWidgetContextMenu")
 				// Send callback event
 				b.sendEvent(Event{
 					Type:     "callback",
@@ -210,7 +212,7 @@ func (b *Bridge) handleSetWidgetStyle(msg Message) {
 	}
 
 	// UI updates must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		// Apply font style if specified
 		if fontStyle, ok := msg.Payload["fontStyle"].(string); ok {
 			switch w := obj.(type) {
@@ -466,7 +468,7 @@ func (b *Bridge) handleSetFontScale(msg Message) {
 	b.scalableTheme.SetFontScale(float32(scale))
 
 	// Refresh all windows to apply the new theme
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		for _, window := range b.windows {
 			window.Canvas().Refresh(window.Content())
 		}
@@ -484,6 +486,14 @@ func (b *Bridge) handleQuit(msg Message) {
 		Success: true,
 	})
 
+	// Other sessions carry on; quitting one only closes it
+	if b.session.id != defaultSessionID {
+		if err := b.sessions.close(b.session.id); err != nil {
+			logBridge.Debugf("Quit: %v", err)
+		}
+		return
+	}
+
 	// Signal quit channel for test mode
 	if b.testMode {
 		select {
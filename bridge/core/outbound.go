@@ -0,0 +1,309 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// Defaults for the outbound queue and event coalescing
+const (
+	defaultOutboundQueueSize = 1024
+	defaultEventMaxRate      = 60 // coalesced events per second, per callback
+)
+
+// outboundItem is a response, event or flush marker waiting to be written to a
+// transport's default Responder
+type outboundItem struct {
+	responder Responder
+	response  *Response
+	event     *Event
+	key       string        // coalescing key; a later event with the same key replaces this one
+	flushed   chan struct{} // closed when a flush marker reaches the writer
+}
+
+// outboundQueue decouples handlers and Fyne callbacks from slow transport
+// writes. Items are written in order by one goroutine, so an event still
+// arrives before the response of the message that caused it. When the client
+// falls behind, queued coalescable events are replaced by newer ones and other
+// events are dropped and counted rather than block the UI thread; responses
+// wait for room, as they must not be lost.
+type outboundQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []*outboundItem
+	keyed    map[string]*outboundItem // queued coalescable events by key
+	limit    int
+	dropped  int  // events dropped since the client was last told
+	closed   bool // set by close; later items are discarded
+}
+
+func newOutboundQueue(limit int) *outboundQueue {
+	q := &outboundQueue{
+		keyed: make(map[string]*outboundItem),
+		limit: limit,
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// setLimit changes how many items may wait to be written
+func (q *outboundQueue) setLimit(limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limit = limit
+	q.notFull.Broadcast()
+}
+
+// pushEvent queues an event without blocking
+func (q *outboundQueue) pushEvent(responder Responder, event Event, key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	if key != "" {
+		if queued, exists := q.keyed[key]; exists {
+			queued.event = &event // latest wins
+			return
+		}
+	} else {
+		// A queued coalescable event for this widget must not be updated past
+		// this one, or a move could overtake the mouseOut or dragEnd after it.
+		// Callback events name no widget, so any callback counts for them.
+		for queuedKey, queued := range q.keyed {
+			if queued.event.WidgetID == event.WidgetID {
+				delete(q.keyed, queuedKey)
+			}
+		}
+	}
+	if len(q.items) >= q.limit {
+		q.dropped++
+		return
+	}
+
+	item := &outboundItem{responder: responder, event: &event, key: key}
+	if key != "" {
+		q.keyed[key] = item
+	}
+	q.items = append(q.items, item)
+	q.notEmpty.Signal()
+}
+
+// pushResponse queues a response, waiting for room if the queue is full
+func (q *outboundQueue) pushResponse(responder Responder, resp Response) {
+	q.pushWaiting(&outboundItem{responder: responder, response: &resp})
+}
+
+// flush waits until everything queued so far has been written
+func (q *outboundQueue) flush() {
+	item := &outboundItem{flushed: make(chan struct{})}
+	q.pushWaiting(item)
+	<-item.flushed
+}
+
+func (q *outboundQueue) pushWaiting(item *outboundItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) >= q.limit && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		if item.flushed != nil {
+			close(item.flushed)
+		}
+		return
+	}
+	q.items = append(q.items, item)
+	q.notEmpty.Signal()
+}
+
+// close stops the writer once what is already queued has been written.
+// Anything queued afterwards is discarded.
+func (q *outboundQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// run writes queued items until the queue is closed
+func (q *outboundQueue) run() {
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		item := q.items[0]
+		q.items[0] = nil
+		q.items = q.items[1:]
+		if item.key != "" && q.keyed[item.key] == item {
+			delete(q.keyed, item.key)
+		}
+		var dropped int
+		if item.responder != nil {
+			dropped = q.dropped
+			q.dropped = 0
+		}
+		q.notFull.Broadcast()
+		q.mu.Unlock()
+
+		if dropped > 0 && item.responder != nil {
+			logEvents.Warnf("Client is falling behind, dropped %d events", dropped)
+			item.responder.SendEvent(Event{
+				Type: "eventsDropped",
+				Data: map[string]interface{}{"count": dropped},
+			})
+		}
+
+		switch {
+		case item.flushed != nil:
+			close(item.flushed)
+		case item.response != nil:
+			if err := item.responder.SendResponse(*item.response); err != nil {
+				logProtocol.Errorf("Error sending response: %v", err)
+			}
+		case item.event != nil:
+			if err := item.responder.SendEvent(*item.event); err != nil {
+				logProtocol.Errorf("Error sending event: %v", err)
+			}
+		}
+	}
+}
+
+// eventThrottle limits how often events with the same key are emitted.
+// Within the interval only the latest event is kept; it is emitted when the
+// interval ends, or earlier if an ordinary event has to go out first, so a
+// final move still arrives before the mouseOut or dragEnd that follows it.
+type eventThrottle struct {
+	mu          sync.Mutex
+	minInterval time.Duration
+	emit        func(event Event, key string)
+	lastSent    map[string]time.Time
+	pending     map[string]*Event
+	order       []string               // pending keys, oldest first
+	timers      map[string]*time.Timer // release timers of pending keys
+	stopped     bool                   // set by stop; later events are dropped
+}
+
+func newEventThrottle(maxRate int, emit func(event Event, key string)) *eventThrottle {
+	t := &eventThrottle{
+		emit:     emit,
+		lastSent: make(map[string]time.Time),
+		pending:  make(map[string]*Event),
+		timers:   make(map[string]*time.Timer),
+	}
+	if maxRate > 0 {
+		t.minInterval = time.Second / time.Duration(maxRate)
+	}
+	return t
+}
+
+// setMaxRate changes how many events per key may be emitted per second, 0 for no limit
+func (t *eventThrottle) setMaxRate(maxRate int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.minInterval = 0
+	if maxRate > 0 {
+		t.minInterval = time.Second / time.Duration(maxRate)
+	}
+}
+
+// inherit gives t the rate limit of another throttle
+func (t *eventThrottle) inherit(other *eventThrottle) {
+	other.mu.Lock()
+	minInterval := other.minInterval
+	other.mu.Unlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.minInterval = minInterval
+}
+
+// send emits the event now if its key has not been used within the interval,
+// otherwise holds it back, replacing any event already held for the key
+func (t *eventThrottle) send(key string, event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped {
+		return
+	}
+	if t.minInterval == 0 {
+		t.emit(event, key)
+		return
+	}
+
+	if _, held := t.pending[key]; held {
+		t.pending[key] = &event
+		return
+	}
+	wait := t.minInterval - time.Since(t.lastSent[key])
+	if wait <= 0 {
+		t.lastSent[key] = time.Now()
+		t.emit(event, key)
+		return
+	}
+
+	t.pending[key] = &event
+	t.order = append(t.order, key)
+	t.timers[key] = time.AfterFunc(wait, func() { t.release(key) })
+}
+
+// release emits the event held for a key, if it is still held
+func (t *eventThrottle) release(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	event, held := t.pending[key]
+	if !held {
+		return
+	}
+	delete(t.pending, key)
+	delete(t.timers, key)
+	for i, k := range t.order {
+		if k == key {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+	t.lastSent[key] = time.Now()
+	t.emit(*event, key)
+}
+
+// releaseAll emits every held event, oldest first
+func (t *eventThrottle) releaseAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range t.order {
+		t.lastSent[key] = time.Now()
+		t.emit(*t.pending[key], key)
+		delete(t.pending, key)
+		t.timers[key].Stop()
+		delete(t.timers, key)
+	}
+	t.order = nil
+}
+
+// stop drops every held event and any sent later, and stops the release timers
+func (t *eventThrottle) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stopped = true
+	for key, timer := range t.timers {
+		timer.Stop()
+		delete(t.timers, key)
+	}
+	t.pending = make(map[string]*Event)
+	t.order = nil
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// recordingResponder keeps what the outbound queue writes, in order
+type recordingResponder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingResponder) SendResponse(resp Response) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, "response "+resp.ID)
+	return nil
+}
+
+func (r *recordingResponder) SendEvent(event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, describeEvent(event))
+	return nil
+}
+
+func describeEvent(event Event) string {
+	if x, ok := event.Data["x"]; ok {
+		return fmt.Sprintf("%s %s x=%v", event.Type, event.WidgetID, x)
+	}
+	return event.Type + " " + event.WidgetID
+}
+
+func pointerMove(widgetID string, x int) Event {
+	return Event{Type: "pointerMove", WidgetID: widgetID, Data: map[string]interface{}{"x": x}}
+}
+
+// writeQueued starts the writer of a queue filled while it was not running,
+// and returns what it wrote
+func writeQueued(q *outboundQueue, responder *recordingResponder) []string {
+	go q.run()
+	q.flush()
+	q.close()
+	return responder.events
+}
+
+// stoppedOutboundQueue is an outboundQueue whose writer has not started, so
+// the test decides what is queued before anything is written
+func stoppedOutboundQueue() *outboundQueue {
+	q := &outboundQueue{
+		keyed: make(map[string]*outboundItem),
+		limit: defaultOutboundQueueSize,
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	return q
+}
+
+func TestOutboundQueueCoalescing(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []Event
+		keys   []string
+		want   []string
+	}{
+		{
+			name:   "latest move wins",
+			events: []Event{pointerMove("w", 1), pointerMove("w", 2), pointerMove("w", 3)},
+			keys:   []string{"move/w", "move/w", "move/w"},
+			want:   []string{"pointerMove w x=3"},
+		},
+		{
+			name:   "move does not overtake a later event for its widget",
+			events: []Event{pointerMove("w", 1), {Type: "mouseOut", WidgetID: "w"}, pointerMove("w", 2)},
+			keys:   []string{"move/w", "", "move/w"},
+			want:   []string{"pointerMove w x=1", "mouseOut w", "pointerMove w x=2"},
+		},
+		{
+			name:   "event for another widget leaves moves coalescing",
+			events: []Event{pointerMove("w", 1), {Type: "mouseOut", WidgetID: "v"}, pointerMove("w", 2)},
+			keys:   []string{"move/w", "", "move/w"},
+			want:   []string{"pointerMove w x=2", "mouseOut v"},
+		},
+		{
+			name:   "callback events count for every callback",
+			events: []Event{{Type: "callback", Data: map[string]interface{}{"x": 1}}, {Type: "callback"}, {Type: "callback", Data: map[string]interface{}{"x": 2}}},
+			keys:   []string{"drag/cb", "", "drag/cb"},
+			want:   []string{"callback  x=1", "callback ", "callback  x=2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := stoppedOutboundQueue()
+			responder := &recordingResponder{}
+			for i, event := range tt.events {
+				q.pushEvent(responder, event, tt.keys[i])
+			}
+
+			if got := writeQueued(q, responder); !slices.Equal(got, tt.want) {
+				t.Errorf("written %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboundQueueDropsEventsWhenFull(t *testing.T) {
+	q := stoppedOutboundQueue()
+	q.limit = 2
+	responder := &recordingResponder{}
+	q.pushEvent(responder, Event{Type: "a"}, "")
+	q.pushEvent(responder, Event{Type: "b"}, "")
+	q.pushEvent(responder, Event{Type: "c"}, "")
+	q.pushEvent(responder, Event{Type: "d"}, "")
+
+	// The client is told of the loss before the next item
+	want := []string{"eventsDropped ", "a ", "b "}
+	if got := writeQueued(q, responder); !slices.Equal(got, want) {
+		t.Errorf("written %q, want %q", got, want)
+	}
+}
+
+func TestEventThrottleReleasesHeldEventFirst(t *testing.T) {
+	var emitted []string
+	throttle := newEventThrottle(1, func(event Event, key string) {
+		emitted = append(emitted, describeEvent(event))
+	})
+	defer throttle.stop()
+
+	throttle.send("move/w", pointerMove("w", 1)) // sent at once
+	throttle.send("move/w", pointerMove("w", 2)) // held for the rest of the second
+	throttle.send("move/w", pointerMove("w", 3)) // replaces the held move
+
+	// What sendEvent does before an ordinary event
+	throttle.releaseAll()
+	emitted = append(emitted, "mouseOut w")
+
+	want := []string{"pointerMove w x=1", "pointerMove w x=3", "mouseOut w"}
+	if !slices.Equal(emitted, want) {
+		t.Errorf("emitted %q, want %q", emitted, want)
+	}
+}
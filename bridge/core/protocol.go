@@ -0,0 +1,281 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"regexp"
+)
+
+// =============================================================================
+// IPC Safeguards #3 & #4: Framing Protocol with CRC32 Validation
+// =============================================================================
+// Frame format: [magic: 4 bytes][length: 4 bytes][crc32: 4 bytes][json: N bytes]
+// - Magic marker lets a reader find the next frame after corrupted input
+// - Length prefix allows detection of message boundaries
+// - CRC32 checksum validates message integrity
+// - Prevents corruption from accidental stdout writes
+
+// frameMagic starts every frame. 0xFF and 0xFE never occur in UTF-8 text, so
+// the marker cannot appear inside a JSON payload. A CBOR payload may contain
+// it, but a false marker is then rejected by the length and checksum checks.
+var frameMagic = []byte{0xFF, 0xFE, 'T', 'S'}
+
+const (
+	frameHeaderSize = 12               // magic + length + crc32
+	maxFrameSize    = 10 * 1024 * 1024 // reject unreasonably large messages
+	frameReadChunk  = 64 * 1024
+)
+
+// writeFramedMessage writes a JSON message with magic marker, length-prefix and CRC32 checksum
+// Format: [magic][uint32 length][uint32 crc32][json bytes]
+func writeFramedMessage(w io.Writer, data []byte) error {
+	// Calculate CRC32 checksum
+	checksum := crc32.ChecksumIEEE(data)
+
+	// Write sync marker
+	if _, err := w.Write(frameMagic); err != nil {
+		return fmt.Errorf("failed to write magic: %w", err)
+	}
+
+	// Write length prefix (4 bytes, big-endian)
+	length := uint32(len(data))
+	if err := binary.Write(w, binary.BigEndian, length); err != nil {
+		return fmt.Errorf("failed to write length: %w", err)
+	}
+
+	// Write CRC32 checksum (4 bytes, big-endian)
+	if err := binary.Write(w, binary.BigEndian, checksum); err != nil {
+		return fmt.Errorf("failed to write checksum: %w", err)
+	}
+
+	// Write JSON payload
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write payload: %w", err)
+	}
+
+	return nil
+}
+
+// frameLoss describes input skipped while resynchronising to the next valid frame
+type frameLoss struct {
+	Reason       string   // why the first skipped frame was rejected
+	BytesSkipped int      // bytes discarded before the next valid frame
+	LostIDs      []string // message IDs found in the discarded bytes (best effort)
+}
+
+// findMessageIDs extracts message IDs from bytes that could not be handled.
+// This is best effort: it returns every "id" string in the JSON text, so IDs
+// from payloads are included and an ID the corruption cut through is missed.
+// CBOR messages are binary and yield no IDs.
+func findMessageIDs(data []byte) []string {
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, match := range lostIDPattern.FindAllSubmatch(data, -1) {
+		id := string(match[1])
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// lostIDPattern matches an "id" field wherever it appears in an object
+var lostIDPattern = regexp.MustCompile(`"id"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+// frameReader reads framed messages from a stream and recovers from corrupted
+// input by scanning forward to the next frame magic whose length and checksum
+// are valid, instead of treating whatever follows as a frame header.
+type frameReader struct {
+	r   io.Reader
+	buf []byte // bytes read but not yet consumed
+	eof bool
+}
+
+func newFrameReader(r io.Reader) *frameReader {
+	return &frameReader{r: r}
+}
+
+// fill reads until at least n bytes are buffered
+func (fr *frameReader) fill(n int) error {
+	for len(fr.buf) < n {
+		if err := fr.readMore(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMore appends the next read to the buffer
+func (fr *frameReader) readMore() error {
+	if fr.eof {
+		return io.EOF
+	}
+	chunk := make([]byte, frameReadChunk)
+	read, err := fr.r.Read(chunk)
+	fr.buf = append(fr.buf, chunk[:read]...)
+	if errors.Is(err, io.EOF) {
+		fr.eof = true
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// fillFrame reads until the frame at the start of the buffer, frameSize bytes
+// long, is complete. Rather than wait for bytes a corrupted length made up, it
+// gives up and reports where a later frame starts if one is already buffered
+// complete and valid: a real frame cannot arrive before the end of the one
+// ahead of it.
+func (fr *frameReader) fillFrame(frameSize int) (laterFrame int, err error) {
+	next := 1 // where to look for the next marker
+	for len(fr.buf) < frameSize {
+		for {
+			i := bytes.Index(fr.buf[next:], frameMagic)
+			if i < 0 {
+				next = max(next, len(fr.buf)-len(frameMagic)+1)
+				break
+			}
+			candidate := next + i
+			complete, valid := validFrameAt(fr.buf, candidate)
+			if !complete {
+				next = candidate // look again once more has arrived
+				break
+			}
+			if valid {
+				return candidate, nil
+			}
+			next = candidate + 1
+		}
+
+		if err := fr.readMore(); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// validFrameAt reports whether buf holds a whole frame starting at i, and if
+// so whether its length and checksum are valid
+func validFrameAt(buf []byte, i int) (complete, valid bool) {
+	if len(buf)-i < frameHeaderSize {
+		return false, false
+	}
+	length := binary.BigEndian.Uint32(buf[i+4 : i+8])
+	if length > maxFrameSize {
+		return true, false
+	}
+	end := i + frameHeaderSize + int(length)
+	if end > len(buf) {
+		return false, false
+	}
+	return true, crc32.ChecksumIEEE(buf[i+frameHeaderSize:end]) == binary.BigEndian.Uint32(buf[i+8:i+12])
+}
+
+// ReadFrame returns the next valid payload. If corrupted input had to be
+// skipped to find it, loss describes what was discarded.
+func (fr *frameReader) ReadFrame() (payload []byte, loss *frameLoss, err error) {
+	var skipped []byte // kept up to maxFrameSize, for finding lost IDs
+
+	discard := func(n int, reason string) {
+		if loss == nil {
+			loss = &frameLoss{Reason: reason}
+		}
+		loss.BytesSkipped += n
+		if len(skipped) < maxFrameSize {
+			skipped = append(skipped, fr.buf[:n]...)
+		}
+		fr.buf = fr.buf[n:]
+	}
+
+	defer func() {
+		if loss == nil {
+			return
+		}
+		loss.LostIDs = findMessageIDs(skipped)
+	}()
+
+	for {
+		// Find the sync marker
+		if err := fr.fill(len(frameMagic)); err != nil {
+			return nil, loss, fr.endOfInput(err)
+		}
+		if !bytes.HasPrefix(fr.buf, frameMagic) {
+			if i := bytes.Index(fr.buf[1:], frameMagic); i >= 0 {
+				discard(i+1, "missing frame marker")
+			} else {
+				// Keep a tail that may be the start of a marker split across reads
+				discard(len(fr.buf)-len(frameMagic)+1, "missing frame marker")
+			}
+			continue
+		}
+
+		if err := fr.fill(frameHeaderSize); err != nil {
+			return nil, loss, fr.endOfInput(err)
+		}
+		length := binary.BigEndian.Uint32(fr.buf[4:8])
+		expectedChecksum := binary.BigEndian.Uint32(fr.buf[8:12])
+
+		if length > maxFrameSize {
+			// The length is garbage, so skip just past this marker and rescan
+			discard(1, fmt.Sprintf("message too large: %d bytes", length))
+			continue
+		}
+
+		frameSize := frameHeaderSize + int(length)
+		laterFrame, err := fr.fillFrame(frameSize)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// Input ended inside this frame; a corrupted length may have
+				// swallowed complete frames, so rescan what is buffered
+				discard(1, "truncated frame")
+				continue
+			}
+			return nil, loss, err
+		}
+		if laterFrame > 0 {
+			discard(laterFrame, fmt.Sprintf("frame length %d runs into the next frame", length))
+			continue
+		}
+
+		// Validate CRC32 checksum
+		actualChecksum := crc32.ChecksumIEEE(fr.buf[frameHeaderSize:frameSize])
+		if actualChecksum != expectedChecksum {
+			// Rescan from just past this marker: the next frame may start inside
+			// what a corrupted length claimed was payload
+			discard(1, fmt.Sprintf("checksum mismatch: expected %d, got %d", expectedChecksum, actualChecksum))
+			continue
+		}
+
+		payload = make([]byte, length)
+		copy(payload, fr.buf[frameHeaderSize:frameSize])
+		fr.buf = fr.buf[frameSize:]
+		return payload, loss, nil
+	}
+}
+
+// endOfInput reports a clean EOF only if no partial frame is left unread
+func (fr *frameReader) endOfInput(err error) error {
+	if errors.Is(err, io.EOF) && len(fr.buf) > 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// sendProtocolError tells the client that input was lost, so it can fail the
+// requests with the given IDs instead of waiting for responses that will never come
+func (b *Bridge) sendProtocolError(reason string, bytesSkipped int, lostIDs []string) {
+	logProtocol.Warnf("%s: skipped %d bytes, lost IDs %v", reason, bytesSkipped, lostIDs)
+	b.sendEvent(Event{
+		Type: "protocolError",
+		Data: map[string]interface{}{
+			"reason":       reason,
+			"bytesSkipped": bytesSkipped,
+			"lostIds":      lostIDs,
+		},
+	})
+}
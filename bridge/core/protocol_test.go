@@ -0,0 +1,168 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// frame returns data as a framed message
+func frame(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := writeFramedMessage(&buf, []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+const (
+	lostMessage = `{"type":"setText","id":"msg_1","payload":{"text":"lost"}}`
+	nextMessage = `{"id":"msg_2","type":"getText","payload":{}}`
+)
+
+func TestReadFrameResync(t *testing.T) {
+	badChecksum := frame(t, lostMessage)
+	badChecksum[frameHeaderSize+2] ^= 0xFF
+
+	longLength := frame(t, lostMessage)
+	binary.BigEndian.PutUint32(longLength[4:8], uint32(len(lostMessage)+100))
+
+	hugeLength := frame(t, lostMessage)
+	binary.BigEndian.PutUint32(hugeLength[4:8], maxFrameSize+1)
+
+	tests := []struct {
+		name    string
+		input   []byte
+		reason  string
+		skipped int
+		lostIDs []string
+	}{
+		{
+			name:    "garbage before a frame",
+			input:   concat([]byte("panic: "+lostMessage+"\n"), frame(t, nextMessage)),
+			reason:  "missing frame marker",
+			skipped: len("panic: " + lostMessage + "\n"),
+			lostIDs: []string{"msg_1"},
+		},
+		{
+			name:    "bad checksum",
+			input:   concat(badChecksum, frame(t, nextMessage)),
+			reason:  "checksum mismatch",
+			skipped: len(badChecksum),
+			lostIDs: []string{"msg_1"},
+		},
+		{
+			name:    "length running into the next frame",
+			input:   concat(longLength, frame(t, nextMessage)),
+			reason:  "runs into the next frame",
+			skipped: len(longLength),
+			lostIDs: []string{"msg_1"},
+		},
+		{
+			name:    "length over the limit",
+			input:   concat(hugeLength, frame(t, nextMessage)),
+			reason:  "message too large",
+			skipped: len(hugeLength),
+			lostIDs: []string{"msg_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newFrameReader(bytes.NewReader(tt.input))
+
+			payload, loss, err := reader.ReadFrame()
+			if err != nil {
+				t.Fatalf("ReadFrame: %v", err)
+			}
+			if string(payload) != nextMessage {
+				t.Errorf("payload = %s, want %s", payload, nextMessage)
+			}
+			if loss == nil {
+				t.Fatal("no loss reported")
+			}
+			if !strings.Contains(loss.Reason, tt.reason) {
+				t.Errorf("reason = %q, want it to contain %q", loss.Reason, tt.reason)
+			}
+			if loss.BytesSkipped != tt.skipped {
+				t.Errorf("bytes skipped = %d, want %d", loss.BytesSkipped, tt.skipped)
+			}
+			if !slices.Equal(loss.LostIDs, tt.lostIDs) {
+				t.Errorf("lost IDs = %v, want %v", loss.LostIDs, tt.lostIDs)
+			}
+
+			if _, _, err := reader.ReadFrame(); !errors.Is(err, io.EOF) {
+				t.Errorf("after the last frame: err = %v, want EOF", err)
+			}
+		})
+	}
+}
+
+func TestReadFrameTruncated(t *testing.T) {
+	truncated := frame(t, lostMessage)
+	truncated = truncated[:len(truncated)-5]
+
+	reader := newFrameReader(bytes.NewReader(concat(frame(t, nextMessage), truncated)))
+
+	payload, loss, err := reader.ReadFrame()
+	if err != nil || loss != nil {
+		t.Fatalf("first frame: loss %v, err %v", loss, err)
+	}
+	if string(payload) != nextMessage {
+		t.Errorf("payload = %s, want %s", payload, nextMessage)
+	}
+
+	payload, loss, err = reader.ReadFrame()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want unexpected EOF", err)
+	}
+	if payload != nil {
+		t.Errorf("payload = %s, want none", payload)
+	}
+	if loss == nil || loss.BytesSkipped == 0 {
+		t.Errorf("loss = %+v, want the truncated frame reported", loss)
+	}
+}
+
+func TestReadFrameMarkerSplitAcrossReads(t *testing.T) {
+	input := concat([]byte("noise"), frame(t, nextMessage))
+	reader := newFrameReader(io.MultiReader(bytes.NewReader(input[:6]), bytes.NewReader(input[6:])))
+
+	payload, loss, err := reader.ReadFrame()
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if string(payload) != nextMessage {
+		t.Errorf("payload = %s, want %s", payload, nextMessage)
+	}
+	if loss == nil || loss.BytesSkipped != len("noise") {
+		t.Errorf("loss = %+v, want %d bytes skipped", loss, len("noise"))
+	}
+}
+
+func TestFindMessageIDs(t *testing.T) {
+	tests := []struct {
+		data string
+		want []string
+	}{
+		{`{"id":"a","type":"x"}`, []string{"a"}},
+		{`{"type":"x","payload":{},"id":"b"}`, []string{"b"}},
+		{`{"type" : "x", "id" : "c"}{"id":"c"}`, []string{"c"}},
+		{`{"widgetId":"w","type":"x"}`, []string{}},
+		{`{"id":"cut off`, []string{}},
+	}
+	for _, tt := range tests {
+		if got := findMessageIDs([]byte(tt.data)); !slices.Equal(got, tt.want) {
+			t.Errorf("findMessageIDs(%s) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// recordEntry is one line of a session recording. Exactly one of Session,
+// Message, Response or Event is set; Direction is "in" for messages, "out" for
+// responses and events, and "session" for the header starting each run.
+type recordEntry struct {
+	Time      time.Time      `json:"time"`
+	Direction string         `json:"direction"`
+	Session   *recordSession `json:"session,omitempty"`
+	Message   *Message       `json:"message,omitempty"`
+	Response  *Response      `json:"response,omitempty"`
+	Event     *Event         `json:"event,omitempty"`
+}
+
+// recordSession heads the entries of one bridge run. Runs recorded to the same
+// file follow one another, and client message IDs restart in each.
+type recordSession struct {
+	PID             int    `json:"pid"`
+	ProtocolVersion string `json:"protocolVersion"`
+}
+
+// recorder appends protocol traffic to a JSON Lines file for --record.
+// A nil recorder records nothing, so callers need not check.
+type recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// openRecorder opens path for appending, creating it if needed, and starts a
+// new session in it
+func openRecorder(path string) (*recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	r := &recorder{file: file, enc: json.NewEncoder(file)}
+	r.write(recordEntry{Direction: "session", Session: &recordSession{
+		PID:             os.Getpid(),
+		ProtocolVersion: protocolVersion,
+	}})
+	return r, nil
+}
+
+func (r *recorder) message(msg Message) {
+	r.write(recordEntry{Direction: "in", Message: &msg})
+}
+
+func (r *recorder) response(resp Response) {
+	r.write(recordEntry{Direction: "out", Response: &resp})
+}
+
+func (r *recorder) event(event Event) {
+	r.write(recordEntry{Direction: "out", Event: &event})
+}
+
+func (r *recorder) write(entry recordEntry) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.enc == nil {
+		return // closed
+	}
+
+	entry.Time = time.Now()
+	if err := r.enc.Encode(entry); err != nil {
+		logBridge.Errorf("Error writing recording: %v", err)
+	}
+}
+
+// close flushes the recording to disk
+func (r *recorder) close() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.enc == nil {
+		return
+	}
+	r.enc = nil
+	r.file.Sync()
+	r.file.Close()
+}
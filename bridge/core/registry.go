@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// HandlerFunc handles one message. It must reply exactly once with
+// b.SendResponse, using the message's ID.
+type HandlerFunc func(b *Bridge, msg Message)
+
+// Payload field types understood by FieldSchema
+const (
+	FieldString = "string"
+	FieldNumber = "number"
+	FieldBool   = "boolean"
+	FieldArray  = "array"
+	FieldObject = "object"
+	FieldBytes  = "bytes" // raw bytes (CBOR), base64 or a data URI (JSON)
+
+	// String IDs that must name something that already exists
+	FieldWidget    = "widget"
+	FieldWindow    = "window"
+	FieldContainer = "container" // a widget that holds children
+)
+
+// FieldSchema describes one payload field of a message type
+type FieldSchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// Required describes a payload field the message cannot do without
+func Required(name, fieldType string) FieldSchema {
+	return FieldSchema{Name: name, Type: fieldType, Required: true}
+}
+
+// Optional describes a payload field that may be left out
+func Optional(name, fieldType string) FieldSchema {
+	return FieldSchema{Name: name, Type: fieldType}
+}
+
+// HandlerSpec registers a message type with the bridge
+type HandlerSpec struct {
+	Type       string
+	Handler    HandlerFunc
+	Payload    []FieldSchema
+	TestOnly   bool   // only available with --headless/--test
+	MainThread bool   // informational: the handler hands work to the main thread itself; dispatch does not read it
+	Scope      string // least gRPC token scope that may send it, ScopeFull if empty
+}
+
+// handlerRegistry maps message types to their handlers. Dispatch, the
+// describe message, capabilities and gRPC Invoke all read it.
+type handlerRegistry struct {
+	mu         sync.RWMutex
+	specs      map[string]HandlerSpec
+	order      []string        // registration order, for describe
+	extensions map[string]bool // types registered with RegisterHandler
+}
+
+func newHandlerRegistry() *handlerRegistry {
+	return &handlerRegistry{
+		specs:      make(map[string]HandlerSpec),
+		extensions: make(map[string]bool),
+	}
+}
+
+// handlers is the registry used by every Bridge. Built-in handlers are added
+// by init in handlers.go; extensions add theirs with RegisterHandler.
+var handlers = newHandlerRegistry()
+
+func (r *handlerRegistry) register(spec HandlerSpec, extension bool) error {
+	if spec.Type == "" || spec.Handler == nil {
+		return fmt.Errorf("handler spec needs a Type and a Handler")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.specs[spec.Type]; exists {
+		return fmt.Errorf("message type %s is already registered", spec.Type)
+	}
+	r.specs[spec.Type] = spec
+	r.order = append(r.order, spec.Type)
+	if extension {
+		r.extensions[spec.Type] = true
+	}
+	return nil
+}
+
+func (r *handlerRegistry) lookup(messageType string) (HandlerSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec, exists := r.specs[messageType]
+	return spec, exists
+}
+
+// types lists the registered message types in registration order
+func (r *handlerRegistry) types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+// describe reports a message type's schema and metadata
+func (r *handlerRegistry) describe(messageType string) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec := r.specs[messageType]
+	payload := spec.Payload
+	if payload == nil {
+		payload = []FieldSchema{}
+	}
+	return map[string]interface{}{
+		"type":       spec.Type,
+		"payload":    payload,
+		"testOnly":   spec.TestOnly,
+		"mainThread": spec.MainThread,
+		"scope":      requiredScope(spec),
+		"extension":  r.extensions[spec.Type],
+	}
+}
+
+// RegisterHandler adds a message type to the bridge, so custom widgets and
+// commands can be added without changing this package. Call it from an init
+// function; it fails if the type is already registered.
+func RegisterHandler(spec HandlerSpec) error {
+	return handlers.register(spec, true)
+}
+
+// handleDescribe reports the registered message types with their payload
+// schemas and metadata, or just one if the payload names a type
+func (b *Bridge) handleDescribe(msg Message) {
+	if messageType, ok := msg.Payload["type"].(string); ok {
+		if _, exists := handlers.lookup(messageType); !exists {
+			b.sendResponse(Response{
+				ID:      msg.ID,
+				Success: false,
+				Error:   fmt.Sprintf("Unknown message type: %s", messageType),
+				Code:    codeUnknownMessageType,
+				Field:   "type",
+			})
+			return
+		}
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: true,
+			Result:  handlers.describe(messageType),
+		})
+		return
+	}
+
+	types := handlers.types()
+	described := make([]interface{}, 0, len(types))
+	for _, messageType := range types {
+		described = append(described, handlers.describe(messageType))
+	}
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result: map[string]interface{}{
+			"protocolVersion": protocolVersion,
+			"handlers":        described,
+		},
+	})
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// replayTimeout bounds how long replay waits for a message's response
+const replayTimeout = 10 * time.Second
+
+// replayUnansweredTimeout is used instead for messages that got no response
+// in the recording, so they do not stall the replay
+const replayUnansweredTimeout = time.Second
+
+// replaySkipped lists message types replay does not send: quitting would end
+// the replay before it can report
+var replaySkipped = map[string]bool{
+	"quit": true,
+}
+
+// recording is the inbound messages of a session, in order, and the
+// responses the bridge sent for them
+type recording struct {
+	messages  []Message
+	responses map[string]Response // message ID -> recorded response
+}
+
+// readRecording loads one session of a --record file: the session'th, counting
+// from 1, or the last if session is 0. Entries written before session headers
+// existed count as one session.
+func readRecording(r io.Reader, session int) (rec *recording, sessions int, err error) {
+	var recordings []*recording
+	startSession := func() {
+		recordings = append(recordings, &recording{responses: make(map[string]Response)})
+		rec = recordings[len(recordings)-1]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxFrameSize*2) // base64 payloads can be large
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry recordEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		if entry.Session != nil || rec == nil {
+			startSession()
+		}
+		switch {
+		case entry.Message != nil:
+			rec.messages = append(rec.messages, *entry.Message)
+		case entry.Response != nil:
+			rec.responses[entry.Response.ID] = *entry.Response
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	sessions = len(recordings)
+	switch {
+	case sessions == 0:
+		return &recording{responses: make(map[string]Response)}, 0, nil
+	case session == 0:
+		return recordings[sessions-1], sessions, nil
+	case session < 0 || session > sessions:
+		return nil, sessions, fmt.Errorf("no session %d, the recording has %d", session, sessions)
+	}
+	return recordings[session-1], sessions, nil
+}
+
+// replayResult summarises a replay
+type replayResult struct {
+	replayed, matched, differed, skipped int
+}
+
+// replay feeds the recorded messages through handleMessage one at a time and
+// writes a line to out for every response that differs from the recording
+func (b *Bridge) replay(rec *recording, out io.Writer) replayResult {
+	var result replayResult
+	for _, msg := range rec.messages {
+		if replaySkipped[msg.Type] {
+			result.skipped++
+			continue
+		}
+
+		expected, answered := rec.responses[msg.ID]
+		timeout := replayTimeout
+		if !answered {
+			timeout = replayUnansweredTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		actual := b.callSync(ctx, msg)
+		timedOut := ctx.Err() != nil
+		cancel()
+		result.replayed++
+
+		var diffs []string
+		switch {
+		case !answered && timedOut:
+			// No response then, none now
+		case !answered:
+			diffs = []string{"response: recorded none, replayed " + replayJSON(actual)}
+		case timedOut:
+			diffs = []string{"response: recorded " + replayJSON(expected) + ", replayed none"}
+		default:
+			diffs = diffValues("", replayNormalize(expected), replayNormalize(actual), nil)
+		}
+
+		if len(diffs) == 0 {
+			result.matched++
+			continue
+		}
+		result.differed++
+		fmt.Fprintf(out, "[%s] %s: response differs\n", msg.ID, msg.Type)
+		for _, diff := range diffs {
+			fmt.Fprintf(out, "  %s\n", diff)
+		}
+	}
+	return result
+}
+
+// replayNormalize converts a response to the generic form it has in a recording
+func replayNormalize(resp Response) interface{} {
+	var generic interface{}
+	data, _ := json.Marshal(resp)
+	json.Unmarshal(data, &generic)
+	return generic
+}
+
+func replayJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// diffValues appends a description of each difference between two decoded
+// JSON values, naming the path to it
+func diffValues(path string, recorded, replayed interface{}, diffs []string) []string {
+	recordedMap, recordedIsMap := recorded.(map[string]interface{})
+	replayedMap, replayedIsMap := replayed.(map[string]interface{})
+	if recordedIsMap && replayedIsMap {
+		keys := make(map[string]bool)
+		for key := range recordedMap {
+			keys[key] = true
+		}
+		for key := range replayedMap {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			recordedValue, inRecorded := recordedMap[key]
+			replayedValue, inReplayed := replayedMap[key]
+			switch {
+			case !inRecorded:
+				diffs = append(diffs, fmt.Sprintf("%s: recorded missing, replayed %s", child, replayJSON(replayedValue)))
+			case !inReplayed:
+				diffs = append(diffs, fmt.Sprintf("%s: recorded %s, replayed missing", child, replayJSON(recordedValue)))
+			default:
+				diffs = diffValues(child, recordedValue, replayedValue, diffs)
+			}
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(recorded, replayed) {
+		if path == "" {
+			path = "response"
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: recorded %s, replayed %s", path, replayJSON(recorded), replayJSON(replayed)))
+	}
+	return diffs
+}
+
+// RunReplay replays one session of a --record file (see readRecording) against
+// a fresh bridge, headless or headed, prints the differences on stdout and
+// exits non-zero if there were any. It returns an error if the replay could
+// not start.
+func RunReplay(testMode bool, path string, session int, opts Options) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	rec, sessions, err := readRecording(file, session)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Nothing is listening on stdout; responses are collected per message
+	bridge := NewBridge(testMode)
+	bridge.SetResponder(discardResponder{})
+	if err := bridge.ApplyOptions(opts); err != nil {
+		return err
+	}
+	defer bridge.reportFatalPanic()
+
+	if session == 0 {
+		session = sessions
+	}
+	logBridge.Infof("Replaying %d messages from session %d of %d in %s", len(rec.messages), session, sessions, path)
+
+	exitCode := 0
+	done := make(chan struct{})
+	go func() {
+		defer bridge.reportFatalPanic()
+		defer close(done)
+		result := bridge.replay(rec, os.Stdout)
+		fmt.Fprintf(os.Stdout, "Replayed %d messages: %d matched, %d differed, %d skipped\n",
+			result.replayed, result.matched, result.differed, result.skipped)
+		if result.differed > 0 {
+			exitCode = 1
+		}
+		if !testMode {
+			fyne.Do(bridge.app.Quit)
+		}
+	}()
+
+	// Headed replay needs the Fyne event loop to render windows
+	if !testMode {
+		bridge.app.Run()
+	}
+	<-done
+	bridge.recorder.close()
+	os.Exit(exitCode)
+	return nil
+}
@@ -1,7 +1,6 @@
-package main
+package core
 
 import (
-	"encoding/base64"
 	"fmt"
 )
 
@@ -17,7 +16,9 @@ func (b *Bridge) handleRegisterResource(msg Message) {
 		return
 	}
 
-	resourceData, ok := msg.Payload["data"].(string)
+	// Raw bytes (CBOR) or base64 image data
+	// Expected format: "data:image/png;base64,..." or just base64 data
+	imgData, ok, err := payloadBytes(msg.Payload, "data")
 	if !ok {
 		b.sendResponse(Response{
 			ID:      msg.ID,
@@ -26,28 +27,11 @@ func (b *Bridge) handleRegisterResource(msg Message) {
 		})
 		return
 	}
-
-	// Decode base64 image data
-	// Expected format: "data:image/png;base64,..." or just base64 data
-	var base64Data string
-	if len(resourceData) > 0 && resourceData[0:5] == "data:" {
-		// Parse data URI format
-		for i := 0; i < len(resourceData); i++ {
-			if resourceData[i] == ',' {
-				base64Data = resourceData[i+1:]
-				break
-			}
-		}
-	} else {
-		base64Data = resourceData
-	}
-
-	imgData, err := base64.StdEncoding.DecodeString(base64Data)
 	if err != nil {
 		b.sendResponse(Response{
 			ID:      msg.ID,
 			Success: false,
-			Error:   fmt.Sprintf("Invalid base64 data: %v", err),
+			Error:   fmt.Sprintf("Invalid image data: %v", err),
 		})
 		return
 	}
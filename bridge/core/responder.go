@@ -0,0 +1,256 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+)
+
+// Responder delivers handler output to a transport.
+// Handlers never write to a transport directly: they call Bridge.sendResponse and
+// Bridge.sendEvent, which hand the message to the Responder routed for it.
+type Responder interface {
+	SendResponse(resp Response) error
+	SendEvent(event Event) error
+}
+
+// framedResponder writes length-prefixed, CRC32-checked frames to a stream.
+// This is the stdio transport.
+type framedResponder struct {
+	mu       sync.Mutex // IPC Safeguard #2: one frame at a time on the stream
+	w        io.Writer
+	encoding string // encodingJSON or encodingCBOR
+}
+
+func newFramedResponder(w io.Writer) *framedResponder {
+	return &framedResponder{w: w, encoding: encodingJSON}
+}
+
+// SetEncoding switches the encoding of subsequent frames
+func (f *framedResponder) SetEncoding(encoding string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.encoding = encoding
+}
+
+func (f *framedResponder) SendResponse(resp Response) error {
+	return f.write(resp)
+}
+
+func (f *framedResponder) SendEvent(event Event) error {
+	return f.write(event)
+}
+
+func (f *framedResponder) write(v interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := encodePayload(f.encoding, v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	// IPC Safeguard #3 & #4: Write with length-prefix framing and CRC32 validation
+	return writeFramedMessage(f.w, data)
+}
+
+// channelResponder delivers responses and events on Go channels.
+// It lets in-process callers and tests receive handler output without a stream.
+type channelResponder struct {
+	responses chan Response
+	events    chan Event // may be nil if the caller is not interested in events
+}
+
+func newChannelResponder(responseBuffer, eventBuffer int) *channelResponder {
+	c := &channelResponder{
+		responses: make(chan Response, responseBuffer),
+	}
+	if eventBuffer > 0 {
+		c.events = make(chan Event, eventBuffer)
+	}
+	return c
+}
+
+func (c *channelResponder) SendResponse(resp Response) error {
+	select {
+	case c.responses <- resp:
+		return nil
+	default:
+		return fmt.Errorf("response channel full, dropping response %s", resp.ID)
+	}
+}
+
+func (c *channelResponder) SendEvent(event Event) error {
+	if c.events == nil {
+		return nil
+	}
+	select {
+	case c.events <- event:
+		return nil
+	default:
+		return fmt.Errorf("event channel full, dropping %s event", event.Type)
+	}
+}
+
+// discardResponder drops everything. Used when a transport has no default
+// stream, e.g. gRPC mode where responses are routed per call and events go
+// through the event bus.
+type discardResponder struct{}
+
+func (discardResponder) SendResponse(resp Response) error { return nil }
+func (discardResponder) SendEvent(event Event) error      { return nil }
+
+// responseRoutes maps in-flight message IDs to the Responder that should
+// receive their response, overriding the bridge's default Responder
+type responseRoutes struct {
+	mu     sync.Mutex
+	routes map[string]Responder // message ID -> responder
+	nextID uint64
+}
+
+func newResponseRoutes() *responseRoutes {
+	return &responseRoutes{
+		routes: make(map[string]Responder),
+	}
+}
+
+// newID allocates a message ID that cannot collide with client-chosen IDs
+func (r *responseRoutes) newID(prefix string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	return fmt.Sprintf("%s_%d", prefix, r.nextID)
+}
+
+func (r *responseRoutes) add(id string, responder Responder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[id] = responder
+}
+
+func (r *responseRoutes) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.routes, id)
+}
+
+// has reports whether a message's response is routed away from the default Responder
+func (r *responseRoutes) has(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.routes[id]
+	return exists
+}
+
+// take returns and forgets the route for a message ID
+func (r *responseRoutes) take(id string) (Responder, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	responder, exists := r.routes[id]
+	if exists {
+		delete(r.routes, id)
+	}
+	return responder, exists
+}
+
+// SetResponder replaces the default Responder that receives events and any
+// response not routed elsewhere
+func (b *Bridge) SetResponder(responder Responder) {
+	b.responderMu.Lock()
+	defer b.responderMu.Unlock()
+	b.responder = responder
+}
+
+func (b *Bridge) defaultResponder() Responder {
+	b.responderMu.RLock()
+	defer b.responderMu.RUnlock()
+	return b.responder
+}
+
+// dispatchTo handles a message and routes its response to the given Responder
+// instead of the default one. The message must carry a unique ID.
+func (b *Bridge) dispatchTo(msg Message, responder Responder) {
+	b.routes.add(msg.ID, responder)
+	b.handleMessage(msg)
+}
+
+// callerIDResponder puts the caller's own message ID back on a response that
+// was dispatched under a bridge-allocated ID
+type callerIDResponder struct {
+	Responder
+	callerID string
+}
+
+func (c callerIDResponder) SendResponse(resp Response) error {
+	resp.ID = c.callerID
+	return c.Responder.SendResponse(resp)
+}
+
+// dispatchAs handles a message from a transport whose clients choose their own
+// IDs. The message runs under a unique bridge-allocated ID, so it cannot collide
+// with another client's, and the response carries the client's ID again.
+// callers, if not nil, lets the client cancel its requests by its own IDs.
+func (b *Bridge) dispatchAs(prefix string, msg Message, responder Responder, callers *callerIDs) {
+	callerID := msg.ID
+	msg = callers.translate(msg)
+	msg.ID = b.routes.newID(prefix)
+	if msg.Payload == nil {
+		msg.Payload = map[string]interface{}{}
+	}
+	callers.add(callerID, msg.ID)
+	defer callers.remove(callerID, msg.ID)
+	b.dispatchTo(msg, callerIDResponder{Responder: responder, callerID: callerID})
+}
+
+// callerIDs remembers the bridge-allocated ID each of one client's requests
+// runs under, so a cancel naming the client's own ID reaches the request.
+// A nil *callerIDs maps nothing.
+type callerIDs struct {
+	mu  sync.Mutex
+	ids map[string]string // caller's ID -> allocated ID
+}
+
+func newCallerIDs() *callerIDs {
+	return &callerIDs{ids: make(map[string]string)}
+}
+
+func (c *callerIDs) add(callerID, id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[callerID] = id
+}
+
+// remove forgets a request, unless the caller has reused its ID since
+func (c *callerIDs) remove(callerID, id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ids[callerID] == id {
+		delete(c.ids, callerID)
+	}
+}
+
+// translate points a cancel at the allocated ID of the request it names
+func (c *callerIDs) translate(msg Message) Message {
+	if c == nil || msg.Type != "cancel" {
+		return msg
+	}
+	requestID, _ := msg.Payload["requestId"].(string)
+	c.mu.Lock()
+	id, exists := c.ids[requestID]
+	c.mu.Unlock()
+	if !exists {
+		return msg
+	}
+
+	payload := maps.Clone(msg.Payload)
+	payload["requestId"] = id
+	msg.Payload = payload
+	return msg
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// defaultSessionID names the session of clients that do not choose one
+const defaultSessionID = "default"
+
+// bridgeSession identifies the client session a Bridge serves
+type bridgeSession struct {
+	id      string
+	name    string
+	owner   string // ID of the gRPC token that opened it, empty for the default session
+	created time.Time
+	done    chan struct{} // closed when the session is closed
+}
+
+func newBridgeSession(id, name, owner string) *bridgeSession {
+	return &bridgeSession{
+		id:      id,
+		name:    name,
+		owner:   owner,
+		created: time.Now(),
+		done:    make(chan struct{}),
+	}
+}
+
+// sessionManager holds the sessions hosted by one bridge process.
+// Each session is a Bridge of its own, with its own widget and window
+// namespace and its own events, sharing the process's Fyne app. The default
+// session is the Bridge the process started with, which is the one stdio,
+// socket and WebSocket clients use.
+type sessionManager struct {
+	mu      sync.Mutex
+	host    *Bridge
+	bridges map[string]*Bridge
+	shared  map[string]sharedWindow // window ID -> window its session shares with the others
+	nextID  int
+}
+
+// sharedWindow is a window one session lets every session address
+type sharedWindow struct {
+	owner *Bridge
+	win   fyne.Window
+}
+
+func newSessionManager(host *Bridge) *sessionManager {
+	return &sessionManager{
+		host:    host,
+		bridges: map[string]*Bridge{defaultSessionID: host},
+		shared:  make(map[string]sharedWindow),
+	}
+}
+
+// open starts an empty session for the token with the given ID
+func (m *sessionManager) open(name, owner string) *Bridge {
+	host := m.host
+	b := newBridge(host.app, host.testMode, host.scalableTheme)
+	b.sessions = m
+	b.crashDir = host.crashDir
+	b.history = host.history
+	b.watchdog = host.watchdog
+	b.throttle.inherit(host.throttle)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	b.session = newBridgeSession("session_"+strconv.Itoa(m.nextID), name, owner)
+	m.bridges[b.session.id] = b
+	return b
+}
+
+// lookup finds a session's Bridge; an empty ID means the default session
+func (m *sessionManager) lookup(id string) (*Bridge, bool) {
+	if id == "" {
+		id = defaultSessionID
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, exists := m.bridges[id]
+	return b, exists
+}
+
+// close ends a session. Its windows close and its event streams end.
+func (m *sessionManager) close(id string) error {
+	if id == defaultSessionID {
+		return fmt.Errorf("the default session cannot be closed")
+	}
+
+	m.mu.Lock()
+	b, exists := m.bridges[id]
+	if exists {
+		delete(m.bridges, id)
+		for windowID, shared := range m.shared {
+			if shared.owner == b {
+				delete(m.shared, windowID)
+			}
+		}
+	}
+	m.mu.Unlock()
+	if !exists {
+		return fmt.Errorf("no session %s", id)
+	}
+
+	close(b.session.done)
+	b.events.close()
+	b.throttle.stop()
+	b.outbound.close()
+
+	b.mu.Lock()
+	windows := make([]fyne.Window, 0, len(b.windows))
+	for _, win := range b.windows {
+		windows = append(windows, win)
+	}
+	b.windows = make(map[string]fyne.Window)
+	b.mu.Unlock()
+
+	// Not waited for, so a handler on the main thread may close its own session
+	fyne.Do(func() {
+		for _, win := range windows {
+			win.Close()
+		}
+	})
+	return nil
+}
+
+// list returns every session's Bridge, oldest first
+func (m *sessionManager) list() []*Bridge {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bridges := make([]*Bridge, 0, len(m.bridges))
+	for _, b := range m.bridges {
+		bridges = append(bridges, b)
+	}
+	sort.Slice(bridges, func(i, j int) bool {
+		return bridges[i].session.created.Before(bridges[j].session.created)
+	})
+	return bridges
+}
+
+// share lets every session address a window by its ID
+func (m *sessionManager) share(owner *Bridge, windowID string, win fyne.Window) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if shared, exists := m.shared[windowID]; exists && shared.owner != owner {
+		return fmt.Errorf("Window %s is already shared by session %s", windowID, shared.owner.session.id)
+	}
+	m.shared[windowID] = sharedWindow{owner: owner, win: win}
+	return nil
+}
+
+// unshare stops sharing a window, if it is still the one shared under its ID
+func (m *sessionManager) unshare(windowID string, win fyne.Window) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if shared, exists := m.shared[windowID]; exists && shared.win == win {
+		delete(m.shared, windowID)
+	}
+}
+
+// sharedWindow finds a window shared by any session
+func (m *sessionManager) sharedWindow(windowID string) (fyne.Window, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shared, exists := m.shared[windowID]
+	return shared.win, exists
+}
+
+// windowCount is the number of windows open across every session
+func (m *sessionManager) windowCount() int {
+	count := 0
+	for _, b := range m.list() {
+		b.mu.RLock()
+		count += len(b.windows)
+		b.mu.RUnlock()
+	}
+	return count
+}
+
+// window finds a window the session may address: one of its own, or one
+// shared by another session. The caller holds b.mu.
+func (b *Bridge) window(windowID string) (fyne.Window, bool) {
+	if win, exists := b.windows[windowID]; exists {
+		return win, true
+	}
+	return b.sessions.sharedWindow(windowID)
+}
@@ -0,0 +1,211 @@
+package core
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// socketAuthTimeout is how long a new client has to send its auth message
+const socketAuthTimeout = 10 * time.Second
+
+// codeUnauthenticated answers a socket client that did not present the token
+const codeUnauthenticated = "UNAUTHENTICATED"
+
+// SocketOptions configures socket mode
+type SocketOptions struct {
+	Token string // token clients must send in their first message, generated if empty
+}
+
+// parseListenAddress splits a --listen value into a network and address.
+// Accepted forms are unix:/path/to.sock and tcp:host:port.
+func parseListenAddress(listen string) (network, address string, err error) {
+	network, address, found := strings.Cut(listen, ":")
+	if !found || address == "" {
+		return "", "", fmt.Errorf("invalid listen address %q, expected unix:/path or tcp:host:port", listen)
+	}
+	switch network {
+	case "unix", "tcp":
+		return network, address, nil
+	default:
+		return "", "", fmt.Errorf("unsupported listen network %q, expected unix or tcp", network)
+	}
+}
+
+// RunSocket serves the framed stdio protocol over a Unix domain socket or
+// TCP connection instead of stdin/stdout. Clients are served one at a time.
+// Each must first send an auth message with the socket token, and then gets
+// its own ready message. stdout carries only the listening address and the
+// token, and a "shutdown" line on stdin stops the bridge, as in gRPC mode.
+// It returns an error if the bridge could not start.
+func RunSocket(testMode bool, listen string, opts Options) error {
+	logBridge.Infof("Starting in socket mode (testMode: %v)", testMode)
+	network, address, err := parseListenAddress(listen)
+	if err != nil {
+		return err
+	}
+	token := opts.Socket.Token
+	if token == "" {
+		token = generateSecureToken(32)
+	}
+
+	var listener net.Listener
+	if network == "unix" {
+		// A socket file left by a previous run would make Listen fail
+		if err := removeStaleSocket(address); err != nil {
+			return err
+		}
+		listener, err = listenUnix(address)
+	} else {
+		listener, err = net.Listen(network, address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+	defer listener.Close()
+
+	// Clients attach and detach over the socket, and the windows should
+	// survive them and the launcher alike
+	opts.Watchdog.HeartbeatOnly = true
+
+	bridge := NewBridge(testMode)
+	if err := bridge.ApplyOptions(opts); err != nil {
+		return err
+	}
+	defer bridge.reportFatalPanic()
+
+	// Send connection info to the launching process via stdout
+	initMsg := map[string]interface{}{
+		"protocol": "socket",
+		"listen":   network + ":" + listener.Addr().String(),
+		"token":    token,
+	}
+	if bridge.websocketInfo != nil {
+		initMsg["websocket"] = bridge.websocketInfo
+	}
+	jsonData, _ := json.Marshal(initMsg)
+	os.Stdout.Write(jsonData)
+	os.Stdout.Write([]byte("\n"))
+	os.Stdout.Sync()
+
+	logSocket.Infof("Listening on %s", initMsg["listen"])
+
+	go func() {
+		defer bridge.reportFatalPanic()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				logSocket.Warnf("Accept failed, no longer listening: %v", err)
+				return
+			}
+			bridge.serveConnection(conn, token)
+		}
+	}()
+
+	// Keep stdin open for shutdown signal
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if scanner.Text() != "shutdown" {
+				continue
+			}
+			logSocket.Infof("Bridge shutting down...")
+			if testMode {
+				select {
+				case bridge.quitChan <- true:
+				default:
+				}
+			} else {
+				bridge.app.Quit()
+			}
+			return
+		}
+		// The launcher can no longer ask us to shut down
+		bridge.watchdog.inputClosed()
+	}()
+
+	// Run the Fyne app, as in stdio mode
+	if !testMode {
+		bridge.app.Run()
+	} else {
+		<-bridge.quitChan
+	}
+	bridge.outbound.flush()
+	return nil
+}
+
+// removeStaleSocket removes a socket file left by a previous run. Anything
+// else at the path is left alone, so a mistyped --listen cannot delete a file.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket, refusing to replace it", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+	return nil
+}
+
+// serveConnection runs the framed protocol on one client connection until it closes
+func (b *Bridge) serveConnection(conn net.Conn, token string) {
+	defer conn.Close()
+	logSocket.Infof("Client connected: %s", conn.RemoteAddr())
+
+	b.SetResponder(newFramedResponder(conn))
+	reader := newFrameReader(conn)
+	if b.authenticateSocket(conn, reader, token) {
+		b.sendReady()
+		b.serveFrames(reader)
+		logSocket.Infof("Client disconnected: %s", conn.RemoteAddr())
+	} else {
+		logSocket.Warnf("Client %s did not authenticate, disconnecting", conn.RemoteAddr())
+	}
+
+	// Write what is still queued for this client before its connection closes
+	b.outbound.flush()
+
+	// Nobody to deliver to until the next client connects
+	b.SetResponder(discardResponder{})
+}
+
+// authenticateSocket reads a client's first message, which must be an auth
+// message carrying the socket token, and answers it. Nothing else the client
+// sends is read, and no ready message is sent, until the token has matched.
+func (b *Bridge) authenticateSocket(conn net.Conn, reader *frameReader, token string) bool {
+	conn.SetReadDeadline(time.Now().Add(socketAuthTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	data, _, err := reader.ReadFrame()
+	if err != nil {
+		return false
+	}
+	var msg Message
+	if err := decodeMessage(data, &msg); err != nil {
+		return false
+	}
+
+	presented, _ := msg.Payload["token"].(string)
+	if msg.Type != "auth" || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "Unauthorized: the first message must be auth with the socket token",
+			Code:    codeUnauthenticated,
+		})
+		return false
+	}
+	b.sendResponse(Response{ID: msg.ID, Success: true})
+	return true
+}
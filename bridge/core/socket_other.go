@@ -0,0 +1,22 @@
+//go:build !unix
+
+package core
+
+import (
+	"net"
+	"os"
+)
+
+// listenUnix creates a Unix domain socket. Without a umask, the file's
+// permissions are narrowed after it is created, where the platform honours them.
+func listenUnix(path string) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
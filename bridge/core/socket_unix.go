@@ -0,0 +1,18 @@
+//go:build unix
+
+package core
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix creates a Unix domain socket only its owner can connect to.
+// The umask applies as the socket file is created, so there is no moment
+// when other users could connect. The umask is process-wide; this runs
+// before the bridge starts anything else that creates files.
+func listenUnix(path string) (net.Listener, error) {
+	old := syscall.Umask(0177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
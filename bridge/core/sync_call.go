@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// callSync dispatches a message through handleMessage and returns the
+// handler's Response to the caller rather than the default Responder.
+// This is how the gRPC service gets real results out of the handle* functions.
+// The request is cancelled when ctx ends, and takes ctx's deadline if it has
+// none of its own.
+func (b *Bridge) callSync(ctx context.Context, msg Message) Response {
+	return b.callSyncAs(ctx, msg, nil)
+}
+
+// callSyncAs is callSync for a client that may cancel its requests by the IDs
+// it gave them, which callers maps to the IDs they run under
+func (b *Bridge) callSyncAs(ctx context.Context, msg Message, callers *callerIDs) Response {
+	callerID := msg.ID
+	msg = callers.translate(msg)
+	msg.ID = b.routes.newID("sync")
+	if msg.Payload == nil {
+		msg.Payload = map[string]interface{}{}
+	}
+	if deadline, ok := ctx.Deadline(); ok && msg.DeadlineMs == 0 {
+		msg.DeadlineMs = max(time.Until(deadline).Milliseconds(), 1)
+	}
+	msg.ctx = ctx
+	callers.add(callerID, msg.ID)
+	defer callers.remove(callerID, msg.ID)
+
+	collector := newChannelResponder(1, 0)
+	defer b.routes.remove(msg.ID)
+	b.dispatchTo(msg, collector)
+
+	var resp Response
+	select {
+	case resp = <-collector.responses:
+	case <-ctx.Done():
+		// The caller gave up, so the handler can too
+		cause, code := errRequestCancelled, codeCancelled
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			cause, code = errDeadlineExceeded, codeDeadlineExceeded
+		}
+		b.cancelRequest(msg.ID, cause)
+		resp = Response{
+			Success: false,
+			Error:   fmt.Sprintf("No response for %s: %v", msg.Type, ctx.Err()),
+			Code:    code,
+		}
+	}
+
+	resp.ID = callerID
+	return resp
+}
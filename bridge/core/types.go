@@ -1,9 +1,7 @@
-package main
+package core
 
 import (
-	"encoding/json"
-	"log"
-	"os"
+	"context"
 	"sync"
 	"time"
 
@@ -16,9 +14,12 @@ import (
 
 // Message types for communication
 type Message struct {
-	ID      string                 `json:"id"`
-	Type    string                 `json:"type"`
-	Payload map[string]interface{} `json:"payload"`
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Payload    map[string]interface{} `json:"payload"`
+	DeadlineMs int64                  `json:"deadlineMs,omitempty"` // give up on the request after this long, 0 for none
+
+	ctx context.Context // set while the message is handled, see Context; before that, the caller's context, if any
 }
 
 type Response struct {
@@ -26,6 +27,8 @@ type Response struct {
 	Success bool                   `json:"success"`
 	Result  map[string]interface{} `json:"result,omitempty"`
 	Error   string                 `json:"error,omitempty"`
+	Code    string                 `json:"code,omitempty"`  // machine-readable error code, see validation.go
+	Field   string                 `json:"field,omitempty"` // payload field the error is about
 }
 
 type Event struct {
@@ -43,7 +46,6 @@ type Bridge struct {
 	contextMenus   map[string]*fyne.Menu          // widget ID -> context menu
 	testMode       bool                           // true for headless testing
 	mu             sync.RWMutex
-	writer         *json.Encoder
 	widgetMeta     map[string]WidgetMetadata      // metadata for testing
 	tableData      map[string][][]string          // table ID -> data
 	listData       map[string][]string            // list ID -> data
@@ -55,6 +57,23 @@ type Bridge struct {
 	quitChan       chan bool                      // signal quit in test mode
 	resources      map[string][]byte              // resource name -> decoded image data
 	scalableTheme  *ScalableTheme                 // custom theme for font scaling
+	events         *eventBus                      // in-bridge event subscribers (gRPC streams)
+	responder      Responder                      // default destination for responses and events
+	responderMu    sync.RWMutex
+	routes         *responseRoutes                // message ID -> responder overriding the default
+	dispatchMu     sync.RWMutex                   // shared by handlers, held exclusively by a batch
+	inBatch        bool                           // a batch is running its steps on the main thread
+	uploads        *uploadManager                 // chunked uploads in progress
+	websocketInfo  map[string]interface{}         // WebSocket url and token, nil if not listening
+	recorder       *recorder                      // --record session log, nil if not recording
+	history        *messageHistory                // recent messages for crash reports
+	crashDir       string                         // where crash reports are written, temp dir if empty
+	watchdog       *watchdog                      // exits the bridge if its parent goes away, nil if disabled
+	outbound       *outboundQueue                 // responses and events waiting for the default Responder
+	throttle       *eventThrottle                 // rate limits pointer moves and drags
+	requests       *requestTracker                // messages whose handlers are running, for cancel and deadlines
+	session        *bridgeSession                 // the client session this Bridge serves
+	sessions       *sessionManager                // every session in the process, shared by their Bridges
 }
 
 // WidgetMetadata stores metadata about widgets for testing
@@ -93,9 +112,9 @@ func NewTappableContainer(content fyne.CanvasObject, callback func()) *TappableC
 // Tapped handles tap events for double-click detection
 func (t *TappableContainer) Tapped(e *fyne.PointEvent) {
 	now := time.Now().UnixMilli()
-	log.Printf("DEBUG: TappableContainer tapped, lastTapTime=%d, now=%d, diff=%d", t.lastTapTime, now, now-t.lastTapTime)
+	logEvents.Debugf("TappableContainer: Tapped, lastTapTime=%d, now=%d, diff=%d", t.lastTapTime, now, now-t.lastTapTime)
 	if now-t.lastTapTime < 500 { // 500ms for double-click
-		log.Printf("DEBUG: Double-click detected! Firing callback")
+		logEvents.Debugf("TappableContainer: Double-click detected, firing callback")
 		if t.DoubleClickCallback != nil {
 			t.DoubleClickCallback()
 		}
@@ -132,7 +151,7 @@ func NewClickableContainer(content fyne.CanvasObject, callback func()) *Clickabl
 
 // Tapped handles single-click events
 func (c *ClickableContainer) Tapped(e *fyne.PointEvent) {
-	log.Printf("[ClickableContainer] Tapped, firing callback")
+	logEvents.Debugf("ClickableContainer: Tapped, firing callback")
 	if c.ClickCallback != nil {
 		c.ClickCallback()
 	}
@@ -243,7 +262,8 @@ func (t *TsyneButton) SetCursor(cursor desktop.Cursor) {
 
 // MouseIn is called when the mouse pointer enters the button
 func (t *TsyneButton) MouseIn(e *desktop.MouseEvent) {
-	log.Printf("[TsyneButton] MouseIn for widget %s at position (%.2f, %.2f)", t.widgetID, e.Position.X, e.Position.Y)
+	defer t.bridge.recoverCallback("TsyneButton.MouseIn")
+	logEvents.Debugf("TsyneButton: MouseIn for widget %s at position (%.2f, %.2f)", t.widgetID, e.Position.X, e.Position.Y)
 
 	// Send callback event if registered
 	if t.onMouseInCallbackId != "" {
@@ -268,10 +288,11 @@ func (t *TsyneButton) MouseIn(e *desktop.MouseEvent) {
 
 // MouseMoved is called when the mouse pointer moves over the button
 func (t *TsyneButton) MouseMoved(e *desktop.MouseEvent) {
+	defer t.bridge.recoverCallback("TsyneButton.MouseMoved")
 	if t.onMouseMovedCallbackId == "" {
 		return
 	}
-	t.bridge.sendEvent(Event{
+	t.bridge.sendCoalescedEvent(t.onMouseMovedCallbackId, Event{
 		Type: "callback",
 		Data: map[string]interface{}{
 			"callbackId": t.onMouseMovedCallbackId,
@@ -285,7 +306,8 @@ func (t *TsyneButton) MouseMoved(e *desktop.MouseEvent) {
 
 // MouseOut is called when the mouse pointer leaves the button
 func (t *TsyneButton) MouseOut() {
-	log.Printf("[TsyneButton] MouseOut for widget %s", t.widgetID)
+	defer t.bridge.recoverCallback("TsyneButton.MouseOut")
+	logEvents.Debugf("TsyneButton: MouseOut for widget %s", t.widgetID)
 
 	// Send callback event if registered
 	if t.onMouseOutCallbackId != "" {
@@ -308,10 +330,11 @@ func (t *TsyneButton) MouseOut() {
 
 // MouseDown is called when a mouse button is pressed over the button
 func (t *TsyneButton) MouseDown(e *desktop.MouseEvent) {
+	defer t.bridge.recoverCallback("TsyneButton.MouseDown")
 	if t.onMouseDownCallbackId == "" {
 		return
 	}
-	log.Printf("[TsyneButton] MouseDown for widget %s button %d", t.widgetID, e.Button)
+	logEvents.Debugf("TsyneButton: MouseDown for widget %s button %d", t.widgetID, e.Button)
 
 	t.bridge.sendEvent(Event{
 		Type: "callback",
@@ -328,10 +351,11 @@ func (t *TsyneButton) MouseDown(e *desktop.MouseEvent) {
 
 // MouseUp is called when a mouse button is released over the button
 func (t *TsyneButton) MouseUp(e *desktop.MouseEvent) {
+	defer t.bridge.recoverCallback("TsyneButton.MouseUp")
 	if t.onMouseUpCallbackId == "" {
 		return
 	}
-	log.Printf("[TsyneButton] MouseUp for widget %s button %d", t.widgetID, e.Button)
+	logEvents.Debugf("TsyneButton: MouseUp for widget %s button %d", t.widgetID, e.Button)
 
 	t.bridge.sendEvent(Event{
 		Type: "callback",
@@ -357,8 +381,9 @@ func (t *TsyneButton) Cursor() desktop.Cursor {
 
 // FocusGained is called when this button gains focus
 func (t *TsyneButton) FocusGained() {
+	defer t.bridge.recoverCallback("TsyneButton.FocusGained")
 	t.focused = true
-	log.Printf("[TsyneButton] FocusGained for widget %s", t.widgetID)
+	logEvents.Debugf("TsyneButton: FocusGained for widget %s", t.widgetID)
 
 	if t.onFocusCallbackId != "" {
 		t.bridge.sendEvent(Event{
@@ -373,8 +398,9 @@ func (t *TsyneButton) FocusGained() {
 
 // FocusLost is called when this button loses focus
 func (t *TsyneButton) FocusLost() {
+	defer t.bridge.recoverCallback("TsyneButton.FocusLost")
 	t.focused = false
-	log.Printf("[TsyneButton] FocusLost for widget %s", t.widgetID)
+	logEvents.Debugf("TsyneButton: FocusLost for widget %s", t.widgetID)
 
 	if t.onFocusCallbackId != "" {
 		t.bridge.sendEvent(Event{
@@ -394,10 +420,10 @@ func (t *TsyneButton) TypedRune(r rune) {
 
 // TypedKey is called when a key is pressed while focused (Focusable interface)
 func (t *TsyneButton) TypedKey(e *fyne.KeyEvent) {
-	log.Printf("[TsyneButton] TypedKey for widget %s key %s", t.widgetID, e.Name)
+	logEvents.Debugf("TsyneButton: TypedKey for widget %s key %s", t.widgetID, e.Name)
 	// Handle Space and Enter to activate the button
 	if e.Name == fyne.KeySpace || e.Name == fyne.KeyReturn || e.Name == fyne.KeyEnter {
-		log.Printf("[TsyneButton] Activating button %s via keyboard", t.widgetID)
+		logEvents.Debugf("TsyneButton: Activating button %s via keyboard", t.widgetID)
 		if t.OnTapped != nil {
 			t.OnTapped()
 		}
@@ -406,10 +432,11 @@ func (t *TsyneButton) TypedKey(e *fyne.KeyEvent) {
 
 // KeyDown is called when a key is pressed while focused (Keyable interface)
 func (t *TsyneButton) KeyDown(e *fyne.KeyEvent) {
+	defer t.bridge.recoverCallback("TsyneButton.KeyDown")
 	if t.onKeyDownCallbackId == "" {
 		return
 	}
-	log.Printf("[TsyneButton] KeyDown for widget %s key %s", t.widgetID, e.Name)
+	logEvents.Debugf("TsyneButton: KeyDown for widget %s key %s", t.widgetID, e.Name)
 
 	t.bridge.sendEvent(Event{
 		Type: "callback",
@@ -422,10 +449,11 @@ func (t *TsyneButton) KeyDown(e *fyne.KeyEvent) {
 
 // KeyUp is called when a key is released while focused (Keyable interface)
 func (t *TsyneButton) KeyUp(e *fyne.KeyEvent) {
+	defer t.bridge.recoverCallback("TsyneButton.KeyUp")
 	if t.onKeyUpCallbackId == "" {
 		return
 	}
-	log.Printf("[TsyneButton] KeyUp for widget %s key %s", t.widgetID, e.Name)
+	logEvents.Debugf("TsyneButton: KeyUp for widget %s key %s", t.widgetID, e.Name)
 
 	t.bridge.sendEvent(Event{
 		Type: "callback",
@@ -472,7 +500,8 @@ func (h *HoverableWrapper) CreateRenderer() fyne.WidgetRenderer {
 
 // MouseIn implements desktop.Hoverable - called when mouse enters the widget
 func (h *HoverableWrapper) MouseIn(ev *desktop.MouseEvent) {
-	log.Printf("[HoverableWrapper] MouseIn for widget %s", h.widgetID)
+	defer h.bridge.recoverCallback("HoverableWrapper.MouseIn")
+	logEvents.Debugf("HoverableWrapper: MouseIn for widget %s", h.widgetID)
 	if h.mouseInHandler != nil {
 		h.mouseInHandler(ev)
 	}
@@ -485,7 +514,8 @@ func (h *HoverableWrapper) MouseIn(ev *desktop.MouseEvent) {
 
 // MouseOut implements desktop.Hoverable - called when mouse exits the widget
 func (h *HoverableWrapper) MouseOut() {
-	log.Printf("[HoverableWrapper] MouseOut for widget %s", h.widgetID)
+	defer h.bridge.recoverCallback("HoverableWrapper.MouseOut")
+	logEvents.Debugf("HoverableWrapper: MouseOut for widget %s", h.widgetID)
 	if h.mouseOutHandler != nil {
 		h.mouseOutHandler()
 	}
@@ -542,14 +572,22 @@ func NewBridge(testMode bool) *Bridge {
 	scalableTheme := NewScalableTheme(1.0)
 	fyneApp.Settings().SetTheme(scalableTheme)
 
-	return &Bridge{
+	b := newBridge(fyneApp, testMode, scalableTheme)
+	b.session = newBridgeSession(defaultSessionID, "", "")
+	b.sessions = newSessionManager(b)
+	return b
+}
+
+// newBridge creates a Bridge with empty widget and window namespaces on an
+// existing Fyne app
+func newBridge(fyneApp fyne.App, testMode bool, scalableTheme *ScalableTheme) *Bridge {
+	b := &Bridge{
 		app:            fyneApp,
 		windows:        make(map[string]fyne.Window),
 		widgets:        make(map[string]fyne.CanvasObject),
 		callbacks:      make(map[string]string),
 		contextMenus:   make(map[string]*fyne.Menu),
 		testMode:       testMode,
-		writer:         json.NewEncoder(os.Stdout),
 		widgetMeta:     make(map[string]WidgetMetadata),
 		tableData:      make(map[string][][]string),
 		listData:       make(map[string][]string),
@@ -561,41 +599,61 @@ func NewBridge(testMode bool) *Bridge {
 		quitChan:       make(chan bool, 1),
 		resources:      make(map[string][]byte),
 		scalableTheme:  scalableTheme,
+		events:         newEventBus(),
+		responder:      discardResponder{}, // transports attach their own with SetResponder
+		routes:         newResponseRoutes(),
+		uploads:        newUploadManager(),
+		history:        newMessageHistory(crashHistorySize),
+		outbound:       newOutboundQueue(defaultOutboundQueueSize),
+		requests:       newRequestTracker(),
 	}
+	b.throttle = newEventThrottle(defaultEventMaxRate, b.emitEvent)
+	return b
 }
 
 func (b *Bridge) sendEvent(event Event) {
-	// IPC Safeguard #2: Mutex protection for stdout writes
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	// Marshal to JSON
-	jsonData, err := json.Marshal(event)
-	if err != nil {
-		log.Printf("Error marshaling event: %v", err)
-		return
-	}
+	// Held back moves and drags go first, so they stay in order with this event
+	b.throttle.releaseAll()
+	b.emitEvent(event, "")
+}
 
-	// IPC Safeguard #3 & #4: Write with length-prefix framing and CRC32 validation
-	if err := writeFramedMessage(os.Stdout, jsonData); err != nil {
-		log.Printf("Error sending event: %v", err)
-	}
+// sendCoalescedEvent sends a high-frequency event such as a pointer move or
+// drag. Events with the same key are rate limited, and only the latest is
+// delivered if the client falls behind.
+func (b *Bridge) sendCoalescedEvent(key string, event Event) {
+	b.throttle.send(key, event)
+}
+
+func (b *Bridge) emitEvent(event Event, key string) {
+	// Fan out to in-bridge subscribers (gRPC SubscribeEvents) first
+	b.events.publish(event)
+	b.recorder.event(event)
+
+	// Queued so a slow client never blocks the Fyne main thread
+	b.outbound.pushEvent(b.defaultResponder(), event, key)
 }
 
 func (b *Bridge) sendResponse(resp Response) {
-	// IPC Safeguard #2: Mutex protection for stdout writes
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	// Marshal to JSON
-	jsonData, err := json.Marshal(resp)
-	if err != nil {
-		log.Printf("Error marshaling response: %v", err)
+	// A cancelled or timed-out request has already been answered
+	if !b.claimResponse(resp.ID) {
+		logProtocol.Debugf("Dropping late response to %s", resp.ID)
+		return
+	}
+	b.deliverResponse(resp)
+}
+
+func (b *Bridge) deliverResponse(resp Response) {
+	// A response routed to a specific caller (gRPC, in-process) bypasses the default Responder
+	b.recorder.response(resp)
+
+	responder, routed := b.routes.take(resp.ID)
+	if !routed {
+		b.throttle.releaseAll()
+		b.outbound.pushResponse(b.defaultResponder(), resp)
 		return
 	}
 
-	// IPC Safeguard #3 & #4: Write with length-prefix framing and CRC32 validation
-	if err := writeFramedMessage(os.Stdout, jsonData); err != nil {
-		log.Printf("Error sending response: %v", err)
+	if err := responder.SendResponse(resp); err != nil {
+		logProtocol.Errorf("Error sending response: %v", err)
 	}
 }
@@ -0,0 +1,346 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxUploadSize caps a chunked upload. Chunks keep each frame under the 10 MB
+	// frame limit; this bounds the total held in memory for one upload.
+	maxUploadSize = 512 * 1024 * 1024
+
+	// maxUploadTotal caps the bytes held by all of a bridge's uploads in progress
+	maxUploadTotal = 1024 * 1024 * 1024
+
+	// uploadIdleTimeout is how long an upload may go without a chunk before it
+	// is discarded as abandoned
+	uploadIdleTimeout = 5 * time.Minute
+
+	// generatedUploadPrefix starts the IDs the bridge assigns. A client
+	// choosing its own ID may not use it, so the two never collide.
+	generatedUploadPrefix = "upload_"
+)
+
+// chunkedUpload is an upload in progress
+type chunkedUpload struct {
+	target    string // "resource" or "tableData"
+	name      string // resource name or table widget ID
+	data      bytes.Buffer
+	hash      hash.Hash // SHA-256 over everything appended so far
+	nextIndex int       // index the next chunk must carry
+	sha256    string    // expected hex digest, if declared at begin
+	lastUsed  time.Time // when it was begun or last appended to
+}
+
+// uploadManager tracks chunked uploads between begin and commit.
+// Payloads too large for a single frame (big images, large tables) are sent
+// as base64 chunks, each with its own CRC32, and checked against a SHA-256
+// of the whole payload when committed. An upload that goes uploadIdleTimeout
+// without a chunk is discarded, and together the uploads in progress may hold
+// at most maxUploadTotal bytes.
+type uploadManager struct {
+	mu      sync.Mutex
+	uploads map[string]*chunkedUpload
+	nextID  int
+	total   int         // bytes held by all uploads in progress
+	expiry  *time.Timer // runs expire while uploads are in progress
+}
+
+func newUploadManager() *uploadManager {
+	return &uploadManager{
+		uploads: make(map[string]*chunkedUpload),
+	}
+}
+
+// touch marks an upload as in use and makes sure it will expire if abandoned.
+// The caller holds um.mu.
+func (um *uploadManager) touch(upload *chunkedUpload) {
+	upload.lastUsed = time.Now()
+	if um.expiry == nil {
+		um.expiry = time.AfterFunc(uploadIdleTimeout, um.expire)
+	}
+}
+
+// remove forgets an upload and the bytes it holds. The caller holds um.mu.
+func (um *uploadManager) remove(uploadID string) *chunkedUpload {
+	upload, exists := um.uploads[uploadID]
+	if !exists {
+		return nil
+	}
+	um.total -= upload.data.Len()
+	delete(um.uploads, uploadID)
+	return upload
+}
+
+// expire discards uploads that have gone uploadIdleTimeout without a chunk,
+// and runs again when the next one would
+func (um *uploadManager) expire() {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	um.expiry = nil
+	var next time.Duration
+	for uploadID, upload := range um.uploads {
+		idle := time.Since(upload.lastUsed)
+		if idle >= uploadIdleTimeout {
+			um.remove(uploadID)
+			logProtocol.Infof("Discarded upload %s after %v without a chunk", uploadID, idle.Round(time.Second))
+			continue
+		}
+		if left := uploadIdleTimeout - idle; next == 0 || left < next {
+			next = left
+		}
+	}
+	if next > 0 {
+		um.expiry = time.AfterFunc(next, um.expire)
+	}
+}
+
+// handleUploadBegin starts a chunked upload
+func (b *Bridge) handleUploadBegin(msg Message) {
+	target, _ := msg.Payload["target"].(string)
+	if target != "resource" && target != "tableData" {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "Missing or invalid 'target' parameter, expected resource or tableData",
+		})
+		return
+	}
+
+	name, ok := msg.Payload["name"].(string)
+	if !ok || name == "" {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "Missing or invalid 'name' parameter",
+		})
+		return
+	}
+
+	expectedHash, _ := msg.Payload["sha256"].(string)
+
+	uploadID, _ := msg.Payload["uploadId"].(string)
+	if strings.HasPrefix(uploadID, generatedUploadPrefix) {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Upload IDs starting with %s are reserved for the bridge", generatedUploadPrefix),
+		})
+		return
+	}
+
+	um := b.uploads
+	um.mu.Lock()
+	if uploadID == "" {
+		um.nextID++
+		uploadID = fmt.Sprintf("%s%d", generatedUploadPrefix, um.nextID)
+	}
+	_, exists := um.uploads[uploadID]
+	if !exists {
+		upload := &chunkedUpload{
+			target: target,
+			name:   name,
+			hash:   sha256.New(),
+			sha256: strings.ToLower(expectedHash),
+		}
+		um.uploads[uploadID] = upload
+		um.touch(upload)
+	}
+	um.mu.Unlock()
+
+	if exists {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Upload %s already in progress", uploadID),
+		})
+		return
+	}
+
+	logProtocol.Debugf("Began upload %s: %s %s", uploadID, target, name)
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result:  map[string]interface{}{"uploadId": uploadID},
+	})
+}
+
+// handleUploadAppend adds one chunk to an upload.
+// Chunks must arrive in order and match their CRC32.
+func (b *Bridge) handleUploadAppend(msg Message) {
+	uploadID, _ := msg.Payload["uploadId"].(string)
+	index, hasIndex := msg.Payload["index"].(float64)
+	chunk, hasData, err := payloadBytes(msg.Payload, "data")
+	checksum, hasChecksum := msg.Payload["crc32"].(float64)
+	if !hasIndex || !hasData || !hasChecksum {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "uploadAppend requires index, data and crc32",
+		})
+		return
+	}
+	if err != nil {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Invalid chunk data: %v", err),
+		})
+		return
+	}
+
+	if actual := crc32.ChecksumIEEE(chunk); actual != uint32(checksum) {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Chunk %d checksum mismatch: expected %d, got %d", int(index), uint32(checksum), actual),
+		})
+		return
+	}
+
+	um := b.uploads
+	um.mu.Lock()
+	upload, exists := um.uploads[uploadID]
+	var errMsg string
+	switch {
+	case !exists:
+		errMsg = fmt.Sprintf("Upload %s not found", uploadID)
+	case int(index) != upload.nextIndex:
+		errMsg = fmt.Sprintf("Chunk %d out of order, expected %d", int(index), upload.nextIndex)
+	case upload.data.Len()+len(chunk) > maxUploadSize:
+		errMsg = fmt.Sprintf("Upload exceeds %d bytes", maxUploadSize)
+		um.remove(uploadID)
+	case um.total+len(chunk) > maxUploadTotal:
+		errMsg = fmt.Sprintf("Uploads in progress would exceed %d bytes", maxUploadTotal)
+	default:
+		upload.data.Write(chunk)
+		upload.hash.Write(chunk)
+		upload.nextIndex++
+		um.total += len(chunk)
+		um.touch(upload)
+	}
+	um.mu.Unlock()
+
+	if errMsg != "" {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   errMsg,
+		})
+		return
+	}
+
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+	})
+}
+
+// handleUploadCommit verifies an upload's SHA-256 and applies it to its target
+func (b *Bridge) handleUploadCommit(msg Message) {
+	uploadID, _ := msg.Payload["uploadId"].(string)
+
+	um := b.uploads
+	um.mu.Lock()
+	upload := um.remove(uploadID)
+	um.mu.Unlock()
+
+	if upload == nil {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Upload %s not found", uploadID),
+		})
+		return
+	}
+
+	expectedHash := upload.sha256
+	if commitHash, ok := msg.Payload["sha256"].(string); ok && commitHash != "" {
+		expectedHash = strings.ToLower(commitHash)
+	}
+	if expectedHash == "" {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "uploadCommit requires sha256 unless it was given to uploadBegin",
+		})
+		return
+	}
+
+	actualHash := hex.EncodeToString(upload.hash.Sum(nil))
+	if actualHash != expectedHash {
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   fmt.Sprintf("Upload %s hash mismatch: expected %s, got %s", uploadID, expectedHash, actualHash),
+		})
+		return
+	}
+
+	logProtocol.Debugf("Committed upload %s: %d bytes in %d chunks", uploadID, upload.data.Len(), upload.nextIndex)
+
+	switch upload.target {
+	case "resource":
+		b.mu.Lock()
+		b.resources[upload.name] = upload.data.Bytes()
+		b.mu.Unlock()
+
+		b.sendResponse(Response{
+			ID:      msg.ID,
+			Success: true,
+		})
+
+	case "tableData":
+		// The uploaded bytes are the JSON rows updateTableData would carry
+		var table [][]string
+		if err := json.Unmarshal(upload.data.Bytes(), &table); err != nil {
+			b.sendResponse(Response{
+				ID:      msg.ID,
+				Success: false,
+				Error:   fmt.Sprintf("Invalid table data: %v", err),
+			})
+			return
+		}
+		rows := make([]interface{}, len(table))
+		for i, row := range table {
+			cells := make([]interface{}, len(row))
+			for j, cell := range row {
+				cells[j] = cell
+			}
+			rows[i] = cells
+		}
+		b.handleUpdateTableData(Message{
+			ID:   msg.ID,
+			Type: "updateTableData",
+			ctx:  msg.ctx,
+			Payload: map[string]interface{}{
+				"id":   upload.name,
+				"data": rows,
+			},
+		})
+	}
+}
+
+// handleUploadAbort discards an upload in progress
+func (b *Bridge) handleUploadAbort(msg Message) {
+	uploadID, _ := msg.Payload["uploadId"].(string)
+
+	b.uploads.mu.Lock()
+	b.uploads.remove(uploadID)
+	b.uploads.mu.Unlock()
+
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+	})
+}
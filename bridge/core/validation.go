@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+)
+
+// Error codes for Response.Code, so clients can react to a failure without
+// parsing its message
+const (
+	codeUnknownMessageType = "UNKNOWN_MESSAGE_TYPE"
+	codeTestModeOnly       = "TEST_MODE_ONLY"
+	codeMissingField       = "MISSING_FIELD"
+	codeWrongType          = "WRONG_TYPE"
+	codeWidgetNotFound     = "WIDGET_NOT_FOUND"
+	codeWindowNotFound     = "WINDOW_NOT_FOUND"
+	codeNotAContainer      = "NOT_A_CONTAINER"
+)
+
+// payloadError is a payload that does not match its message type's schema
+type payloadError struct {
+	code    string
+	field   string
+	message string
+}
+
+func (e *payloadError) Error() string {
+	return e.message
+}
+
+// response turns the error into a failed Response for a message
+func (e *payloadError) response(id string) Response {
+	return Response{
+		ID:      id,
+		Success: false,
+		Error:   e.message,
+		Code:    e.code,
+		Field:   e.field,
+	}
+}
+
+// validatePayload checks a payload against a schema before its handler runs,
+// so handlers can assert required fields' types without panicking
+func (b *Bridge) validatePayload(schema []FieldSchema, payload map[string]interface{}) *payloadError {
+	for _, field := range schema {
+		value := payload[field.Name]
+		if value == nil {
+			if field.Required {
+				return &payloadError{
+					code:    codeMissingField,
+					field:   field.Name,
+					message: fmt.Sprintf("Missing required field: %s", field.Name),
+				}
+			}
+			continue
+		}
+
+		if !fieldHasType(value, field.Type) {
+			return &payloadError{
+				code:    codeWrongType,
+				field:   field.Name,
+				message: fmt.Sprintf("Field %s must be a %s, got %T", field.Name, fieldTypeName(field.Type), value),
+			}
+		}
+
+		if id, isString := value.(string); isString {
+			if err := b.checkReference(field, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldHasType reports whether a decoded JSON or CBOR value has a schema type
+func fieldHasType(value interface{}, fieldType string) bool {
+	switch fieldType {
+	case FieldString, FieldWidget, FieldWindow, FieldContainer:
+		_, ok := value.(string)
+		return ok
+	case FieldNumber:
+		_, ok := value.(float64)
+		return ok
+	case FieldBool:
+		_, ok := value.(bool)
+		return ok
+	case FieldArray:
+		_, ok := value.([]interface{})
+		return ok
+	case FieldObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case FieldBytes:
+		switch value.(type) {
+		case []byte, string:
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// fieldTypeName describes a schema type for error messages
+func fieldTypeName(fieldType string) string {
+	switch fieldType {
+	case FieldWidget, FieldWindow, FieldContainer:
+		return fieldType + " ID string"
+	case FieldBytes:
+		return "bytes or base64 string"
+	default:
+		return fieldType
+	}
+}
+
+// checkReference checks that an ID field names an existing widget, window or container
+func (b *Bridge) checkReference(field FieldSchema, id string) *payloadError {
+	switch field.Type {
+	case FieldWidget, FieldContainer:
+		b.mu.RLock()
+		obj, exists := b.widgets[id]
+		b.mu.RUnlock()
+		if !exists {
+			return &payloadError{
+				code:    codeWidgetNotFound,
+				field:   field.Name,
+				message: fmt.Sprintf("Widget not found: %s", id),
+			}
+		}
+		if _, isContainer := obj.(*fyne.Container); field.Type == FieldContainer && !isContainer {
+			return &payloadError{
+				code:    codeNotAContainer,
+				field:   field.Name,
+				message: fmt.Sprintf("Widget %s is not a container", id),
+			}
+		}
+	case FieldWindow:
+		b.mu.RLock()
+		_, exists := b.window(id)
+		b.mu.RUnlock()
+		if !exists {
+			return &payloadError{
+				code:    codeWindowNotFound,
+				field:   field.Name,
+				message: fmt.Sprintf("Window not found: %s", id),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// watchdogInterval is how often the watchdog checks on the parent process
+const watchdogInterval = time.Second
+
+// WatchdogOptions configures how the bridge notices that its parent is gone
+type WatchdogOptions struct {
+	Enabled          bool
+	Grace            time.Duration // how long to wait before exiting once the parent looks gone
+	HeartbeatTimeout time.Duration // exit if no message arrives for this long, 0 to disable
+	HeartbeatOnly    bool          // ignore the parent process and stdin, for a bridge meant to outlive its launcher
+}
+
+// watchdog exits the bridge when the process that launched it goes away, so a
+// SIGKILLed Node process does not leave orphaned bridges and windows behind.
+// The parent is gone if the bridge is reparented, if stdin is closed, or, with
+// a heartbeat timeout, if the client stops sending messages (ping will do).
+// A socket-mode bridge outlives its launcher, so only the heartbeat applies.
+type watchdog struct {
+	bridge    *Bridge
+	opts      WatchdogOptions
+	parentPID int
+
+	mu        sync.Mutex
+	lastSeen  time.Time
+	lost      string    // why the parent looks gone, empty while it is alive
+	lostAt    time.Time // when it was first noticed
+	permanent bool      // the parent exited or closed stdin; a message cannot undo that
+}
+
+func newWatchdog(bridge *Bridge, opts WatchdogOptions) *watchdog {
+	return &watchdog{
+		bridge:    bridge,
+		opts:      opts,
+		parentPID: os.Getppid(),
+		lastSeen:  time.Now(),
+	}
+}
+
+// start checks on the parent in the background until the bridge exits
+func (w *watchdog) start() {
+	logBridge.Infof("Watching parent process %d (grace %v, heartbeat timeout %v)",
+		w.parentPID, w.opts.Grace, w.opts.HeartbeatTimeout)
+	go func() {
+		ticker := time.NewTicker(watchdogInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			w.check()
+		}
+	}()
+}
+
+func (w *watchdog) check() {
+	// An orphan is adopted by init or a subreaper, so its parent PID changes
+	if ppid := os.Getppid(); ppid != w.parentPID && !w.opts.HeartbeatOnly {
+		w.parentLost(fmt.Sprintf("parent process %d exited", w.parentPID))
+	}
+
+	w.mu.Lock()
+	if w.opts.HeartbeatTimeout > 0 && w.lost == "" && time.Since(w.lastSeen) > w.opts.HeartbeatTimeout {
+		w.lost = fmt.Sprintf("no message from the client for %v", w.opts.HeartbeatTimeout)
+		w.lostAt = time.Now()
+		logBridge.Warnf("%s, exiting in %v unless it returns", w.lost, w.opts.Grace)
+	}
+	lost, lostAt := w.lost, w.lostAt
+	w.mu.Unlock()
+
+	if lost != "" && time.Since(lostAt) >= w.opts.Grace {
+		w.bridge.exitOrphaned(lost)
+	}
+}
+
+// seen records that the client is alive. It cancels a missed heartbeat, but
+// not a dead parent process or closed stdin.
+func (w *watchdog) seen() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastSeen = time.Now()
+	if w.lost != "" && !w.permanent {
+		logBridge.Infof("Client is back")
+		w.lost = ""
+	}
+}
+
+// inputClosed reports that stdin reached EOF, so the parent can no longer
+// talk to the bridge
+func (w *watchdog) inputClosed() {
+	if w == nil || w.opts.HeartbeatOnly {
+		return
+	}
+	w.parentLost("stdin was closed")
+}
+
+func (w *watchdog) parentLost(reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.permanent {
+		return
+	}
+	w.permanent = true
+	w.lost = reason
+	w.lostAt = time.Now()
+	logBridge.Warnf("%s, exiting in %v", reason, w.opts.Grace)
+}
+
+// exitOrphaned closes the bridge's windows and exits the process
+func (b *Bridge) exitOrphaned(reason string) {
+	logBridge.Warnf("%s, closing windows and exiting", reason)
+
+	if !b.testMode {
+		// A stuck handler may hold the lock; the windows go with the process anyway
+		var windows []fyne.Window
+		if b.mu.TryRLock() {
+			for _, win := range b.windows {
+				windows = append(windows, win)
+			}
+			b.mu.RUnlock()
+		}
+
+		// Give the main thread a moment, but do not hang if it is stuck
+		closed := make(chan struct{})
+		fyne.Do(func() {
+			for _, win := range windows {
+				win.Close()
+			}
+			close(closed)
+		})
+		select {
+		case <-closed:
+		case <-time.After(2 * time.Second):
+			logBridge.Warnf("Main thread did not close the windows in time")
+		}
+	}
+
+	b.recorder.close()
+	os.Exit(0)
+}
+
+// handlePing answers a heartbeat. Any message counts as one; ping exists so an
+// idle client can keep the watchdog satisfied and measure round trips.
+func (b *Bridge) handlePing(msg Message) {
+	b.sendResponse(Response{
+		ID:      msg.ID,
+		Success: true,
+		Result: map[string]interface{}{
+			"pong": true,
+			"time": time.Now().UnixMilli(),
+		},
+	})
+}
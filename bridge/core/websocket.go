@@ -0,0 +1,168 @@
+package core
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// wsResponder sends responses and events to one WebSocket client as JSON text messages
+type wsResponder struct {
+	mu   sync.Mutex // one message at a time on the connection
+	conn *websocket.Conn
+}
+
+func (w *wsResponder) SendResponse(resp Response) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return websocket.JSON.Send(w.conn, resp)
+}
+
+func (w *wsResponder) SendEvent(event Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return websocket.JSON.Send(w.conn, event)
+}
+
+// wsToken extracts the auth token from a WebSocket upgrade request.
+// Tools can send an Authorization header, as gRPC clients do; browsers cannot
+// set headers on a WebSocket, so they pass ?token= instead.
+func wsToken(req *http.Request) string {
+	if token := req.Header.Get("Authorization"); token != "" {
+		return strings.TrimPrefix(token, "Bearer ")
+	}
+	return req.URL.Query().Get("token")
+}
+
+// WebSocketOptions configures the optional WebSocket listener
+type WebSocketOptions struct {
+	Address string // host:port, empty to disable
+	Token   string // generated if empty
+}
+
+// startWebSocket starts the WebSocket listener if one was requested and
+// records how to reach it for the ready message and connection info
+func (b *Bridge) startWebSocket(opts WebSocketOptions) error {
+	if opts.Address == "" {
+		return nil
+	}
+	token := opts.Token
+	if token == "" {
+		token = generateSecureToken(32)
+	}
+
+	address, err := startWebSocketServer(opts.Address, token, b)
+	if err != nil {
+		return fmt.Errorf("failed to listen for WebSocket clients on %s: %w", opts.Address, err)
+	}
+	b.websocketInfo = map[string]interface{}{
+		"url":   "ws://" + address + "/",
+		"token": token,
+	}
+	return nil
+}
+
+// startWebSocketServer listens on address and serves the bridge's Message,
+// Response and Event JSON over WebSocket, one JSON object per text message.
+// Every client receives all events, so a browser tool can observe a live window.
+// It returns the address actually listened on.
+func startWebSocketServer(address, token string, bridge *Bridge) (string, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", err
+	}
+
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			if subtle.ConstantTimeCompare([]byte(wsToken(req)), []byte(token)) != 1 {
+				logWebSocket.Warnf("Rejected unauthorized connection from %s", req.RemoteAddr)
+				return fmt.Errorf("unauthorized")
+			}
+			return nil
+		},
+		Handler: bridge.serveWebSocket,
+	}
+
+	go func() {
+		if err := http.Serve(listener, server); err != nil {
+			logWebSocket.Warnf("Server stopped: %v", err)
+		}
+	}()
+
+	logWebSocket.Infof("Listening on %s", listener.Addr())
+	return listener.Addr().String(), nil
+}
+
+// serveWebSocket handles messages from one WebSocket client until it disconnects
+func (b *Bridge) serveWebSocket(conn *websocket.Conn) {
+	defer conn.Close()
+	logWebSocket.Infof("Client connected: %s", conn.Request().RemoteAddr)
+
+	responder := &wsResponder{conn: conn}
+
+	subID, events := b.events.subscribe(nil)
+	defer b.events.unsubscribe(subID)
+	go func() {
+		for event := range events {
+			if err := responder.SendEvent(event); err != nil {
+				logWebSocket.Warnf("Error sending event: %v", err)
+			}
+		}
+	}()
+
+	// Messages are handled in order by one goroutine while this one keeps
+	// reading, so a cancel can reach a request that is still running
+	callers := newCallerIDs()
+	queue := make(chan Message, framedQueueSize)
+	drained := make(chan struct{})
+	go func() {
+		defer b.reportFatalPanic()
+		defer close(drained)
+		for msg := range queue {
+			b.dispatchAs("ws", msg, responder, callers)
+		}
+	}()
+	defer func() {
+		close(queue)
+		<-drained
+	}()
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			if !errors.Is(err, io.EOF) {
+				logWebSocket.Warnf("Error reading message: %v", err)
+			}
+			break
+		}
+
+		var msg Message
+		if err := decodeMessage(data, &msg); err != nil {
+			logWebSocket.Warnf("Error parsing message: %v", err)
+			responder.SendEvent(Event{
+				Type: "protocolError",
+				Data: map[string]interface{}{
+					"reason":       fmt.Sprintf("invalid message: %v", err),
+					"bytesSkipped": len(data),
+					"lostIds":      findMessageIDs(data),
+				},
+			})
+			continue
+		}
+
+		if msg.Type == "cancel" {
+			b.dispatchAs("ws", msg, responder, callers)
+			continue
+		}
+		queue <- msg
+	}
+
+	logWebSocket.Infof("Client disconnected: %s", conn.Request().RemoteAddr)
+}
@@ -1,4 +1,4 @@
-package main
+package core
 
 import (
 	"bytes"
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"log"
 	"net/url"
 	"os"
 	"strings"
@@ -23,6 +22,7 @@ func (b *Bridge) handleCreateButton(msg Message) {
 	callbackID, hasCallback := msg.Payload["callbackId"].(string)
 
 	btn := widget.NewButton(text, func() {
+		defer b.recoverCallback("createButton")
 		if hasCallback {
 			b.sendEvent(Event{
 				Type:     "callback",
@@ -91,6 +91,7 @@ func (b *Bridge) handleCreateEntry(msg Message) {
 	// Set onSubmit callback if provided (triggered on Enter key)
 	if callbackID, ok := msg.Payload["callbackId"].(string); ok {
 		entry.OnSubmitted = func(text string) {
+			defer b.recoverCallback("createEntry")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{
@@ -116,6 +117,7 @@ func (b *Bridge) handleCreateEntry(msg Message) {
 	// If double-click callback is provided, wrap in a double-tappable container
 	if doubleClickCallbackID, ok := msg.Payload["doubleClickCallbackId"].(string); ok {
 		callback := func() {
+			defer b.recoverCallback("createEntry")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{
@@ -189,6 +191,7 @@ func (b *Bridge) handleCreatePasswordEntry(msg Message) {
 	// Set onSubmit callback if provided (triggered on Enter key)
 	if callbackID, ok := msg.Payload["callbackId"].(string); ok {
 		entry.OnSubmitted = func(text string) {
+			defer b.recoverCallback("createPasswordEntry")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{
@@ -322,6 +325,7 @@ func (b *Bridge) handleCreateCheckbox(msg Message) {
 	callbackID, hasCallback := msg.Payload["callbackId"].(string)
 
 	check := widget.NewCheck(text, func(checked bool) {
+		defer b.recoverCallback("createCheckbox")
 		if hasCallback {
 			b.sendEvent(Event{
 				Type:     "callback",
@@ -358,6 +362,7 @@ func (b *Bridge) handleCreateSelect(msg Message) {
 	}
 
 	sel := widget.NewSelect(options, func(selected string) {
+		defer b.recoverCallback("createSelect")
 		if hasCallback {
 			b.sendEvent(Event{
 				Type:     "callback",
@@ -397,6 +402,7 @@ func (b *Bridge) handleCreateSlider(msg Message) {
 
 	if hasCallback {
 		slider.OnChanged = func(value float64) {
+			defer b.recoverCallback("createSlider")
 			b.sendEvent(Event{
 				Type:     "callback",
 				WidgetID: widgetID,
@@ -707,6 +713,7 @@ func (b *Bridge) handleCreateForm(msg Message) {
 
 	if submitCallbackID, ok := msg.Payload["submitCallbackId"].(string); ok {
 		onSubmit = func() {
+			defer b.recoverCallback("createForm")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{"callbackId": submitCallbackID},
@@ -716,6 +723,7 @@ func (b *Bridge) handleCreateForm(msg Message) {
 
 	if cancelCallbackID, ok := msg.Payload["cancelCallbackId"].(string); ok {
 		onCancel = func() {
+			defer b.recoverCallback("createForm")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{"callbackId": cancelCallbackID},
@@ -878,7 +886,7 @@ func (b *Bridge) handleCreateImage(msg Message) {
 		img.SetMinSize(fyne.NewSize(float32(width), float32(height)))
 	} else if !hasPath || path == "" {
 		// If path is empty, create a blank image (will be updated with base64 later)
-		log.Printf("[Image] Creating blank image widget %s (will be updated with base64)", widgetID)
+		logWidgets.Debugf("Image: Creating blank image widget %s (will be updated with base64)", widgetID)
 		img = canvas.NewImageFromImage(nil)
 	} else if strings.HasPrefix(path, "data:") {
 		// Handle base64 data URI directly
@@ -896,7 +904,7 @@ func (b *Bridge) handleCreateImage(msg Message) {
 		base64Data := parts[1]
 		imageData, err := base64.StdEncoding.DecodeString(base64Data)
 		if err != nil {
-			log.Printf("[Image] Error decoding base64: %v", err)
+			logWidgets.Warnf("Image: Error decoding base64: %v", err)
 			b.sendResponse(Response{
 				ID:      msg.ID,
 				Success: false,
@@ -908,7 +916,7 @@ func (b *Bridge) handleCreateImage(msg Message) {
 		// Decode image data
 		decodedImg, _, err := image.Decode(bytes.NewReader(imageData))
 		if err != nil {
-			log.Printf("[Image] Error decoding image: %v", err)
+			logWidgets.Warnf("Image: Error decoding image: %v", err)
 			b.sendResponse(Response{
 				ID:      msg.ID,
 				Success: false,
@@ -927,7 +935,7 @@ func (b *Bridge) handleCreateImage(msg Message) {
 		// Load image from file - use file reading for better SVG support
 		data, err := os.ReadFile(path)
 		if err != nil {
-			log.Printf("[Image] Error reading file %s: %v", path, err)
+			logWidgets.Warnf("Image: Error reading file %s: %v", path, err)
 			b.sendResponse(Response{
 				ID:      msg.ID,
 				Success: false,
@@ -972,8 +980,8 @@ func (b *Bridge) handleCreateImage(msg Message) {
 
 		if hasDragCallback {
 			dragCallback = func(x, y float32) {
-				log.Printf("[Image] Dragging image widget %s at (%f, %f), sending callback %s", widgetID, x, y, dragCallbackID)
-				b.sendEvent(Event{
+				defer b.recoverCallback("createImage")
+				b.sendCoalescedEvent(dragCallbackID, Event{
 					Type: "callback",
 					Data: map[string]interface{}{
 						"callbackId": dragCallbackID,
@@ -986,7 +994,8 @@ func (b *Bridge) handleCreateImage(msg Message) {
 
 		if hasDragEndCallback {
 			dragEndCallback = func(x, y float32) {
-				log.Printf("[Image] DragEnd for image widget %s at (%f, %f), sending callback %s", widgetID, x, y, dragEndCallbackID)
+				defer b.recoverCallback("createImage")
+				logEvents.Debugf("Image: DragEnd for image widget %s at (%f, %f), sending callback %s", widgetID, x, y, dragEndCallbackID)
 				b.sendEvent(Event{
 					Type: "callback",
 					Data: map[string]interface{}{
@@ -1000,7 +1009,8 @@ func (b *Bridge) handleCreateImage(msg Message) {
 
 		if hasClickCallback {
 			clickCallback = func() {
-				log.Printf("[Image] Clicked image widget %s, sending callback %s", widgetID, clickCallbackID)
+				defer b.recoverCallback("createImage")
+				logEvents.Debugf("Image: Clicked image widget %s, sending callback %s", widgetID, clickCallbackID)
 				b.sendEvent(Event{
 					Type: "callback",
 					Data: map[string]interface{}{
@@ -1014,6 +1024,7 @@ func (b *Bridge) handleCreateImage(msg Message) {
 	} else if hasClickCallback {
 		// Wrap image in a clickable container for single-click support only
 		callback := func() {
+			defer b.recoverCallback("createImage")
 			b.sendEvent(Event{
 				Type: "callback",
 				Data: map[string]interface{}{
@@ -1143,6 +1154,7 @@ func (b *Bridge) handleCreateRadioGroup(msg Message) {
 
 	// Create radio group with change callback
 	radio := widget.NewRadioGroup(options, func(selected string) {
+		defer b.recoverCallback("createRadioGroup")
 		if hasCallback {
 			b.sendEvent(Event{
 				Type: "callback",
@@ -1308,6 +1320,7 @@ func (b *Bridge) handleCreateToolbar(msg Message) {
 			action := widget.NewToolbarAction(
 				nil, // Icon (we'll keep it simple for now)
 				func() {
+					defer b.recoverCallback("createToolbar")
 					b.sendEvent(Event{
 						Type: "callback",
 						Data: map[string]interface{}{
@@ -1473,6 +1486,7 @@ func (b *Bridge) handleCreateList(msg Message) {
 	// Handle selection callback if provided
 	if callbackID, ok := msg.Payload["callbackId"].(string); ok {
 		list.OnSelected = func(itemID widget.ListItemID) {
+			defer b.recoverCallback("createList")
 			b.mu.RLock()
 			listData := b.listData[id]
 			b.mu.RUnlock()
@@ -1531,6 +1545,7 @@ func (b *Bridge) handleCreateMenu(msg Message) {
 		capturedHasCallback := hasCallback
 
 		menuItem := fyne.NewMenuItem(label, func() {
+			defer b.recoverCallback("createMenu")
 			if capturedHasCallback {
 				b.sendEvent(Event{
 					Type: "callback",
@@ -1,15 +1,15 @@
-package main
+package core
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
 	"fmt"
 	"image"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -102,7 +102,7 @@ func (b *Bridge) handleSetText(msg Message) {
 	}
 
 	// UI updates must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		switch w := actualWidget.(type) {
 		case *widget.Label:
 			w.SetText(text)
@@ -208,7 +208,7 @@ func (b *Bridge) handleSetChecked(msg Message) {
 	if check, ok := obj.(*widget.Check); ok {
 		// UI updates must happen on the main thread
 		// Temporarily disable OnChanged to prevent infinite loops when setting initial state
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			originalCallback := check.OnChanged
 			check.OnChanged = nil
 			check.SetChecked(checked)
@@ -277,7 +277,7 @@ func (b *Bridge) handleSetValue(msg Message) {
 
 	if slider, ok := obj.(*widget.Slider); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			slider.SetValue(value)
 		})
 		b.sendResponse(Response{
@@ -343,7 +343,7 @@ func (b *Bridge) handleSetProgress(msg Message) {
 
 	if pb, ok := obj.(*widget.ProgressBar); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			pb.SetValue(value)
 		})
 		b.sendResponse(Response{
@@ -409,7 +409,7 @@ func (b *Bridge) handleSetSelected(msg Message) {
 
 	if sel, ok := obj.(*widget.Select); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			sel.SetSelected(selected)
 		})
 		b.sendResponse(Response{
@@ -475,7 +475,7 @@ func (b *Bridge) handleSetRadioSelected(msg Message) {
 
 	if radio, ok := obj.(*widget.RadioGroup); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			radio.SetSelected(selected)
 		})
 		b.sendResponse(Response{
@@ -547,7 +547,7 @@ func (b *Bridge) handleUpdateTableData(msg Message) {
 
 	if table, ok := obj.(*widget.Table); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			table.Refresh()
 		})
 		b.sendResponse(Response{
@@ -614,7 +614,7 @@ func (b *Bridge) handleUpdateListData(msg Message) {
 
 	if list, ok := obj.(*widget.List); ok {
 		// UI updates must happen on the main thread
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			list.Refresh()
 		})
 		b.sendResponse(Response{
@@ -630,11 +630,15 @@ func (b *Bridge) handleUpdateListData(msg Message) {
 	}
 }
 
+// imageFetchTimeout bounds an updateImage url fetch when the request has no
+// earlier deadline
+const imageFetchTimeout = 30 * time.Second
+
 func (b *Bridge) handleUpdateImage(msg Message) {
 	widgetID := msg.Payload["widgetId"].(string)
 
 	// Check which type of image source is provided
-	imageData, hasImageData := msg.Payload["imageData"].(string)
+	imgBytes, hasImageData, imageDataErr := payloadBytes(msg.Payload, "imageData")
 	path, hasPath := msg.Payload["path"].(string)
 	resourceName, hasResource := msg.Payload["resource"].(string)
 	svgString, hasSVG := msg.Payload["svg"].(string)
@@ -711,8 +715,20 @@ func (b *Bridge) handleUpdateImage(msg Message) {
 			return
 		}
 	} else if hasURL && urlString != "" {
-		// Remote URL - fetch and decode
-		resp, err := http.Get(urlString)
+		// Remote URL - fetch and decode, giving up if the request is cancelled
+		// or the server is too slow
+		fetchCtx, cancel := context.WithTimeout(msg.Context(), imageFetchTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, urlString, nil)
+		if err != nil {
+			b.sendResponse(Response{
+				ID:      msg.ID,
+				Success: false,
+				Error:   fmt.Sprintf("Invalid URL: %v", err),
+			})
+			return
+		}
+		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
 			b.sendResponse(Response{
 				ID:      msg.ID,
@@ -751,33 +767,13 @@ func (b *Bridge) handleUpdateImage(msg Message) {
 			})
 			return
 		}
-	} else if hasImageData && imageData != "" {
-		// Base64 data URI (backwards compatible)
-		var base64Data string
-		if strings.HasPrefix(imageData, "data:") {
-			// Split on comma to separate header from data
-			parts := strings.SplitN(imageData, ",", 2)
-			if len(parts) != 2 {
-				b.sendResponse(Response{
-					ID:      msg.ID,
-					Success: false,
-					Error:   "Invalid data URL format",
-				})
-				return
-			}
-			base64Data = parts[1]
-		} else {
-			// Assume it's already base64 without the data URL prefix
-			base64Data = imageData
-		}
-
-		// Decode base64
-		imgBytes, err := base64.StdEncoding.DecodeString(base64Data)
-		if err != nil {
+	} else if hasImageData {
+		// Raw bytes (CBOR), or a base64 data URI (backwards compatible)
+		if imageDataErr != nil {
 			b.sendResponse(Response{
 				ID:      msg.ID,
 				Success: false,
-				Error:   fmt.Sprintf("Failed to decode base64: %v", err),
+				Error:   fmt.Sprintf("Failed to decode image data: %v", imageDataErr),
 			})
 			return
 		}
@@ -801,6 +797,12 @@ func (b *Bridge) handleUpdateImage(msg Message) {
 		return
 	}
 
+	// The request was cancelled or timed out while fetching or decoding;
+	// it has been answered already, so leave the image as it was
+	if msg.Context().Err() != nil {
+		return
+	}
+
 	// Find the actual canvas.Image widget
 	// It might be wrapped in a ClickableContainer or DraggableContainer
 	var imgWidget *canvas.Image
@@ -831,7 +833,7 @@ func (b *Bridge) handleUpdateImage(msg Message) {
 	}
 
 	// UI updates must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		imgWidget.Image = decodedImg
 		imgWidget.Refresh()
 	})
@@ -914,7 +916,7 @@ func (b *Bridge) handleGetContainerObjects(msg Message) {
 
 	// Get container objects (child widget IDs)
 	var childIDs []string
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		for _, childObj := range container.Objects {
 			// Find the widget ID for this object (reverse lookup)
 			b.mu.RLock()
@@ -1046,7 +1048,7 @@ func (b *Bridge) handleAnnounce(msg Message) {
 	// In the future, this could integrate with native platform TTS
 	text, _ := msg.Payload["text"].(string)
 	if b.testMode {
-		log.Printf("[TTS] %s", text)
+		logA11y.Infof("Speaking: %s", text)
 	}
 
 	b.sendResponse(Response{
@@ -1098,7 +1100,7 @@ func (b *Bridge) handleSetPointerEnter(msg Message) {
 	widgetMeta.CustomData["announceOnHover"] = true
 	b.widgetMeta[widgetID] = widgetMeta
 
-	log.Printf("[setPointerEnter] Stored hover metadata for widget %s (wrapping deferred)", widgetID)
+	logWidgets.Debugf("setPointerEnter: Stored hover metadata for widget %s (wrapping deferred)", widgetID)
 
 	// Unlock before sending response
 	b.mu.Unlock()
@@ -1118,7 +1120,7 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 	// In test mode, don't wrap widgets to avoid threading issues
 	if b.testMode {
 		b.mu.Unlock()
-		log.Printf("[processHoverWrappers] Test mode - skipping wrapping")
+		logWidgets.Debugf("processHoverWrappers: Test mode - skipping wrapping")
 		b.sendResponse(Response{
 			ID:      msg.ID,
 			Success: true,
@@ -1133,17 +1135,17 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 		if widgetMeta.CustomData != nil && widgetMeta.CustomData["announceOnHover"] == true {
 			obj, exists := b.widgets[widgetID]
 			if !exists {
-				log.Printf("[processHoverWrappers] Widget %s not found in map", widgetID)
+				logWidgets.Debugf("processHoverWrappers: Widget %s not found in map", widgetID)
 				continue
 			}
 
 			// Check if already a TsyneButton or wrapped
 			if _, alreadyTsyne := obj.(*TsyneButton); alreadyTsyne {
-				log.Printf("[processHoverWrappers] Widget %s already a TsyneButton", widgetID)
+				logWidgets.Debugf("processHoverWrappers: Widget %s already a TsyneButton", widgetID)
 				continue
 			}
 			if _, alreadyWrapped := obj.(*HoverableWrapper); alreadyWrapped {
-				log.Printf("[processHoverWrappers] Widget %s already wrapped", widgetID)
+				logWidgets.Debugf("processHoverWrappers: Widget %s already wrapped", widgetID)
 				continue
 			}
 
@@ -1151,7 +1153,7 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 
 			// For buttons, create a TsyneButton
 			if btn, isButton := obj.(*widget.Button); isButton {
-				log.Printf("[processHoverWrappers] Converting button %s to TsyneButton", widgetID)
+				logWidgets.Debugf("processHoverWrappers: Converting button %s to TsyneButton", widgetID)
 				tsyneBtn := NewTsyneButton(btn.Text, btn.OnTapped, b, widgetID)
 				tsyneBtn.Importance = btn.Importance
 				tsyneBtn.Icon = btn.Icon
@@ -1165,7 +1167,7 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 				replacement = tsyneBtn
 			} else {
 				// For other widgets, use the wrapper approach
-				log.Printf("[processHoverWrappers] Wrapping widget %s with HoverableWrapper", widgetID)
+				logWidgets.Debugf("processHoverWrappers: Wrapping widget %s with HoverableWrapper", widgetID)
 				replacement = NewHoverableWrapper(obj, b, widgetID)
 			}
 
@@ -1174,7 +1176,7 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 
 			// Replace in parent container
 			parentID, hasParent := b.childToParent[widgetID]
-			log.Printf("[processHoverWrappers] Widget %s: hasParent=%v, parentID=%s", widgetID, hasParent, parentID)
+			logWidgets.Debugf("processHoverWrappers: Widget %s: hasParent=%v, parentID=%s", widgetID, hasParent, parentID)
 			if hasParent {
 				parentObj, exists := b.widgets[parentID]
 				if exists {
@@ -1183,7 +1185,7 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 						for i, child := range objects {
 							if child == obj {
 								objects[i] = replacement
-								log.Printf("[processHoverWrappers] Replaced widget %s at index %d in parent %s", widgetID, i, parentID)
+								logWidgets.Debugf("processHoverWrappers: Replaced widget %s at index %d in parent %s", widgetID, i, parentID)
 								// Note: Don't refresh here - windows haven't been shown yet
 								// The refresh will happen naturally when the window is shown
 								wrappedCount++
@@ -1196,7 +1198,7 @@ func (b *Bridge) handleProcessHoverWrappers(msg Message) {
 		}
 	}
 
-	log.Printf("[processHoverWrappers] Wrapped %d widgets with HoverableWrapper", wrappedCount)
+	logWidgets.Debugf("processHoverWrappers: Wrapped %d widgets with HoverableWrapper", wrappedCount)
 
 	// Unlock before sending response to avoid deadlock (sendResponse also acquires the lock)
 	b.mu.Unlock()
@@ -1289,7 +1291,7 @@ func (b *Bridge) handleSetWidgetHoverable(msg Message) {
 
 	// Check if already a TsyneButton - if so, update its callback IDs
 	if tsyneBtn, alreadyTsyne := obj.(*TsyneButton); alreadyTsyne {
-		log.Printf("[setWidgetHoverable] Widget %s is already a TsyneButton, updating callback IDs", widgetID)
+		logWidgets.Debugf("setWidgetHoverable: Widget %s is already a TsyneButton, updating callback IDs", widgetID)
 		// Update Hoverable callback IDs
 		if onMouseInCallbackId != "" {
 			tsyneBtn.onMouseInCallbackId = onMouseInCallbackId
@@ -1330,7 +1332,7 @@ func (b *Bridge) handleSetWidgetHoverable(msg Message) {
 		return
 	}
 	if _, alreadyWrapped := obj.(*HoverableWrapper); alreadyWrapped {
-		log.Printf("[setWidgetHoverable] Widget %s is already wrapped", widgetID)
+		logWidgets.Debugf("setWidgetHoverable: Widget %s is already wrapped", widgetID)
 		b.mu.Unlock()
 		b.sendResponse(Response{
 			ID:      msg.ID,
@@ -1342,7 +1344,7 @@ func (b *Bridge) handleSetWidgetHoverable(msg Message) {
 	// In test mode, skip the actual wrapping but return success
 	// Events will be handled differently in test mode
 	if b.testMode {
-		log.Printf("[setWidgetHoverable] Test mode - skipping widget wrapping for %s", widgetID)
+		logWidgets.Debugf("setWidgetHoverable: Test mode - skipping widget wrapping for %s", widgetID)
 		b.mu.Unlock()
 		b.sendResponse(Response{
 			ID:      msg.ID,
@@ -1356,7 +1358,7 @@ func (b *Bridge) handleSetWidgetHoverable(msg Message) {
 
 	// For buttons, create a TsyneButton with appropriate callback IDs
 	if btn, isButton := obj.(*widget.Button); isButton {
-		log.Printf("[setWidgetHoverable] Converting button %s to TsyneButton", widgetID)
+		logWidgets.Debugf("setWidgetHoverable: Converting button %s to TsyneButton", widgetID)
 		tsyneBtn := NewTsyneButton(btn.Text, btn.OnTapped, b, widgetID)
 		tsyneBtn.Importance = btn.Importance
 		tsyneBtn.Icon = btn.Icon
@@ -1390,7 +1392,7 @@ func (b *Bridge) handleSetWidgetHoverable(msg Message) {
 		replacement = tsyneBtn
 	} else {
 		// For other widgets, use the wrapper approach
-		log.Printf("[setWidgetHoverable] Wrapping widget %s with HoverableWrapper", widgetID)
+		logWidgets.Debugf("setWidgetHoverable: Wrapping widget %s with HoverableWrapper", widgetID)
 		replacement = NewHoverableWrapper(obj, b, widgetID)
 	}
 
@@ -1406,7 +1408,7 @@ func (b *Bridge) handleSetWidgetHoverable(msg Message) {
 				for i, child := range container.Objects {
 					if child == obj {
 						container.Objects[i] = replacement
-						log.Printf("[setWidgetHoverable] Replaced widget %s at index %d in parent %s", widgetID, i, parentID)
+						logWidgets.Debugf("setWidgetHoverable: Replaced widget %s at index %d in parent %s", widgetID, i, parentID)
 						break
 					}
 				}
@@ -1,4 +1,4 @@
-package main
+package core
 
 import (
 	"fyne.io/fyne/v2"
@@ -21,7 +21,7 @@ func (b *Bridge) handleShowWidget(msg Message) {
 		return
 	}
 
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		obj.Show()
 	})
 
@@ -47,7 +47,7 @@ func (b *Bridge) handleHideWidget(msg Message) {
 		return
 	}
 
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		obj.Hide()
 	})
 
@@ -78,7 +78,7 @@ func (b *Bridge) handleEnableWidget(msg Message) {
 	// If we have a separate entry reference (from TappableEntry), use that
 	if hasEntry {
 		if entry, ok := entryObj.(*widget.Entry); ok {
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				entry.Enable()
 			})
 			b.sendResponse(Response{
@@ -91,7 +91,7 @@ func (b *Bridge) handleEnableWidget(msg Message) {
 
 	// Try to enable the widget directly
 	if disableable, ok := obj.(fyne.Disableable); ok {
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			disableable.Enable()
 		})
 		b.sendResponse(Response{
@@ -128,7 +128,7 @@ func (b *Bridge) handleDisableWidget(msg Message) {
 	// If we have a separate entry reference (from TappableEntry), use that
 	if hasEntry {
 		if entry, ok := entryObj.(*widget.Entry); ok {
-			fyne.DoAndWait(func() {
+			b.runOnMain(msg.Context(), func() {
 				entry.Disable()
 			})
 			b.sendResponse(Response{
@@ -141,7 +141,7 @@ func (b *Bridge) handleDisableWidget(msg Message) {
 
 	// Try to disable the widget directly
 	if disableable, ok := obj.(fyne.Disableable); ok {
-		fyne.DoAndWait(func() {
+		b.runOnMain(msg.Context(), func() {
 			disableable.Disable()
 		})
 		b.sendResponse(Response{
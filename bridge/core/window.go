@@ -1,4 +1,4 @@
-package main
+package core
 
 import (
 	"fmt"
@@ -16,7 +16,7 @@ func (b *Bridge) handleCreateWindow(msg Message) {
 	var win fyne.Window
 
 	// Window creation must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		win = b.app.NewWindow(title)
 
 		// Set window size if provided
@@ -35,19 +35,32 @@ func (b *Bridge) handleCreateWindow(msg Message) {
 		win.SetCloseIntercept(func() {
 			b.mu.Lock()
 			delete(b.windows, windowID)
-			windowCount := len(b.windows)
 			b.mu.Unlock()
+			b.sessions.unshare(windowID, win)
 
 			// Close this window
 			win.Close()
 
-			// If no more windows, quit the application
-			if windowCount == 0 {
+			// If no session has a window left, quit the application
+			if b.sessions.windowCount() == 0 {
 				b.app.Quit()
 			}
 		})
 	})
 
+	// A shared window can be addressed by its ID from every session
+	if shared, ok := msg.Payload["shared"].(bool); ok && shared {
+		if err := b.sessions.share(b, windowID, win); err != nil {
+			b.runOnMain(msg.Context(), win.Close)
+			b.sendResponse(Response{
+				ID:      msg.ID,
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
 	b.mu.Lock()
 	b.windows[windowID] = win
 	b.mu.Unlock()
@@ -63,7 +76,7 @@ func (b *Bridge) handleShowWindow(msg Message) {
 	windowID := msg.Payload["windowId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -76,7 +89,7 @@ func (b *Bridge) handleShowWindow(msg Message) {
 	}
 
 	// Showing window must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		win.Show()
 	})
 
@@ -91,7 +104,7 @@ func (b *Bridge) handleSetWindowTitle(msg Message) {
 	title := msg.Payload["title"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -104,7 +117,7 @@ func (b *Bridge) handleSetWindowTitle(msg Message) {
 	}
 
 	// Setting window title must happen on the main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		win.SetTitle(title)
 	})
 
@@ -119,7 +132,7 @@ func (b *Bridge) handleSetWindowFullScreen(msg Message) {
 	fullscreen := msg.Payload["fullscreen"].(bool)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -144,7 +157,7 @@ func (b *Bridge) handleCaptureWindow(msg Message) {
 	filePath := msg.Payload["filePath"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -159,7 +172,7 @@ func (b *Bridge) handleCaptureWindow(msg Message) {
 	var img image.Image
 
 	// Canvas capture must happen on main thread
-	fyne.DoAndWait(func() {
+	b.runOnMain(msg.Context(), func() {
 		img = win.Canvas().Capture()
 	})
 
@@ -195,7 +208,7 @@ func (b *Bridge) handleCenterWindow(msg Message) {
 	windowID := msg.Payload["windowId"].(string)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -221,7 +234,7 @@ func (b *Bridge) handleResizeWindow(msg Message) {
 	height := msg.Payload["height"].(float64)
 
 	b.mu.RLock()
-	win, exists := b.windows[windowID]
+	win, exists := b.window(windowID)
 	b.mu.RUnlock()
 
 	if !exists {
@@ -27,6 +27,8 @@ type Response struct {
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	Result        map[string]string      `protobuf:"bytes,3,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Code          string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`   // machine-readable error code, e.g. MISSING_FIELD
+	Field         string                 `protobuf:"bytes,5,opt,name=field,proto3" json:"field,omitempty"` // payload field the error is about
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,6 +84,20 @@ func (x *Response) GetResult() map[string]string {
 	return nil
 }
 
+func (x *Response) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Response) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
 // Window operations
 type CreateWindowRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -2571,253 +2587,1141 @@ func (*QuitRequest) Descriptor() ([]byte, []int) {
 	return file_proto_bridge_proto_rawDescGZIP(), []int{43}
 }
 
-var File_proto_bridge_proto protoreflect.FileDescriptor
-
-const file_proto_bridge_proto_rawDesc = "" +
-	"\n" +
-	"\x12proto/bridge.proto\x12\x06bridge\"\xab\x01\n" +
-	"\bResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x124\n" +
-	"\x06result\x18\x03 \x03(\v2\x1c.bridge.Response.ResultEntryR\x06result\x1a9\n" +
-	"\vResultEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x95\x01\n" +
-	"\x13CreateWindowRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
-	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n" +
-	"\x06height\x18\x04 \x01(\x05R\x06height\x12\x1d\n" +
-	"\n" +
-	"fixed_size\x18\x05 \x01(\bR\tfixedSize\"0\n" +
-	"\x11ShowWindowRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\"M\n" +
-	"\x11SetContentRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x1b\n" +
-	"\twidget_id\x18\x02 \x01(\tR\bwidgetId\"`\n" +
-	"\x13ResizeWindowRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x14\n" +
-	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
-	"\x06height\x18\x03 \x01(\x05R\x06height\"J\n" +
-	"\x15SetWindowTitleRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\"2\n" +
-	"\x13CenterWindowRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\"Y\n" +
-	"\x1aSetWindowFullScreenRequest\x12\x1b\n" +
-	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x1e\n" +
-	"\n" +
-	"fullscreen\x18\x02 \x01(\bR\n" +
-	"fullscreen\"\xb3\x02\n" +
-	"\x12CreateImageRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12!\n" +
-	"\vinline_data\x18\x02 \x01(\fH\x00R\n" +
-	"inlineData\x12%\n" +
-	"\rresource_name\x18\x03 \x01(\tH\x00R\fresourceName\x12\x14\n" +
-	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x16\n" +
-	"\x06height\x18\x05 \x01(\x05R\x06height\x12\x1f\n" +
-	"\vcallback_id\x18\x06 \x01(\tR\n" +
-	"callbackId\x12(\n" +
-	"\x10drag_callback_id\x18\a \x01(\tR\x0edragCallbackId\x123\n" +
-	"\x16double_tap_callback_id\x18\b \x01(\tR\x13doubleTapCallbackIdB\b\n" +
-	"\x06source\"w\n" +
-	"\x12CreateLabelRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
-	"\x04text\x18\x02 \x01(\tR\x04text\x12\x12\n" +
-	"\x04bold\x18\x03 \x01(\bR\x04bold\x12\x1c\n" +
-	"\talignment\x18\x04 \x01(\x05R\talignment\"\x85\x01\n" +
-	"\x13CreateButtonRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
-	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1f\n" +
-	"\vcallback_id\x18\x03 \x01(\tR\n" +
-	"callbackId\x12\x1c\n" +
-	"\timportant\x18\x04 \x01(\bR\timportant\"\xc4\x01\n" +
-	"\x12CreateEntryRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12 \n" +
-	"\vplaceholder\x18\x02 \x01(\tR\vplaceholder\x12\x1f\n" +
-	"\vcallback_id\x18\x03 \x01(\tR\n" +
-	"callbackId\x12\x14\n" +
-	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x1c\n" +
-	"\tmultiline\x18\x05 \x01(\bR\tmultiline\x12\x1a\n" +
-	"\bpassword\x18\x06 \x01(\bR\bpassword\"0\n" +
-	"\x11CreateVBoxRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"0\n" +
-	"\x11CreateHBoxRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"\x83\x01\n" +
-	"\x15CreateCheckboxRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
-	"\x04text\x18\x02 \x01(\tR\x04text\x12\x18\n" +
-	"\achecked\x18\x03 \x01(\bR\achecked\x12\x1f\n" +
-	"\vcallback_id\x18\x04 \x01(\tR\n" +
-	"callbackId\"\x89\x01\n" +
-	"\x13CreateSelectRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x18\n" +
-	"\aoptions\x18\x02 \x03(\tR\aoptions\x12\x1a\n" +
-	"\bselected\x18\x03 \x01(\x05R\bselected\x12\x1f\n" +
-	"\vcallback_id\x18\x04 \x01(\tR\n" +
-	"callbackId\"A\n" +
-	"\x17RegisterResourceRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
-	"\x04data\x18\x02 \x01(\fR\x04data\"/\n" +
-	"\x19UnregisterResourceRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"\x85\x01\n" +
-	"\x12UpdateImageRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12!\n" +
-	"\vinline_data\x18\x02 \x01(\fH\x00R\n" +
-	"inlineData\x12%\n" +
-	"\rresource_name\x18\x03 \x01(\tH\x00R\fresourceNameB\b\n" +
-	"\x06source\"A\n" +
-	"\x0eSetTextRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
-	"\x04text\x18\x02 \x01(\tR\x04text\"-\n" +
-	"\x0eGetTextRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"U\n" +
-	"\x0fGetTextResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x12\x12\n" +
-	"\x04text\x18\x03 \x01(\tR\x04text\"G\n" +
-	"\x12SetProgressRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x01R\x05value\"1\n" +
-	"\x12GetProgressRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"[\n" +
-	"\x13GetProgressResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x12\x14\n" +
-	"\x05value\x18\x03 \x01(\x01R\x05value\"J\n" +
-	"\x11SetCheckedRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x18\n" +
-	"\achecked\x18\x02 \x01(\bR\achecked\"0\n" +
-	"\x11GetCheckedRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"^\n" +
-	"\x12GetCheckedResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x12\x18\n" +
-	"\achecked\x18\x03 \x01(\bR\achecked\"1\n" +
-	"\x12ClickWidgetRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"B\n" +
-	"\x0fTypeTextRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
-	"\x04text\x18\x02 \x01(\tR\x04text\"5\n" +
-	"\x16DoubleTapWidgetRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"6\n" +
-	"\x17RightClickWidgetRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"b\n" +
-	"\x11DragWidgetRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x17\n" +
-	"\adelta_x\x18\x02 \x01(\x05R\x06deltaX\x12\x17\n" +
-	"\adelta_y\x18\x03 \x01(\x05R\x06deltaY\"S\n" +
-	"\x17RegisterCustomIdRequest\x12\x1b\n" +
-	"\tcustom_id\x18\x01 \x01(\tR\bcustomId\x12\x1b\n" +
-	"\twidget_id\x18\x02 \x01(\tR\bwidgetId\"C\n" +
-	"\x11FindWidgetRequest\x12\x1a\n" +
-	"\bselector\x18\x01 \x01(\tR\bselector\x12\x12\n" +
-	"\x04type\x18\x02 \x01(\tR\x04type\"c\n" +
-	"\x12FindWidgetResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1d\n" +
-	"\n" +
-	"widget_ids\x18\x03 \x03(\tR\twidgetIds\"3\n" +
-	"\x14GetWidgetInfoRequest\x12\x1b\n" +
-	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"\xfa\x01\n" +
-	"\x12WidgetInfoResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x12\x0e\n" +
-	"\x02id\x18\x03 \x01(\tR\x02id\x12\x12\n" +
-	"\x04type\x18\x04 \x01(\tR\x04type\x12\x12\n" +
-	"\x04text\x18\x05 \x01(\tR\x04text\x12\f\n" +
-	"\x01x\x18\x06 \x01(\x02R\x01x\x12\f\n" +
-	"\x01y\x18\a \x01(\x02R\x01y\x12\x14\n" +
-	"\x05width\x18\b \x01(\x02R\x05width\x12\x16\n" +
-	"\x06height\x18\t \x01(\x02R\x06height\x12\x18\n" +
-	"\avisible\x18\n" +
-	" \x01(\bR\avisible\x12\x18\n" +
-	"\aenabled\x18\v \x01(\bR\aenabled\"\x16\n" +
-	"\x14GetAllWidgetsRequest\"u\n" +
-	"\x15GetAllWidgetsResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x12,\n" +
-	"\awidgets\x18\x03 \x03(\v2\x12.bridge.WidgetInfoR\awidgets\"D\n" +
-	"\n" +
-	"WidgetInfo\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
-	"\x04text\x18\x03 \x01(\tR\x04text\"\x9e\x01\n" +
-	"\x05Event\x12\x12\n" +
-	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1b\n" +
-	"\twidget_id\x18\x02 \x01(\tR\bwidgetId\x12+\n" +
-	"\x04data\x18\x03 \x03(\v2\x17.bridge.Event.DataEntryR\x04data\x1a7\n" +
-	"\tDataEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"4\n" +
-	"\x11EventSubscription\x12\x1f\n" +
-	"\vevent_types\x18\x01 \x03(\tR\n" +
-	"eventTypes\"\r\n" +
-	"\vQuitRequest2\xd3\x11\n" +
-	"\rBridgeService\x12=\n" +
-	"\fCreateWindow\x12\x1b.bridge.CreateWindowRequest\x1a\x10.bridge.Response\x129\n" +
-	"\n" +
-	"ShowWindow\x12\x19.bridge.ShowWindowRequest\x1a\x10.bridge.Response\x129\n" +
-	"\n" +
-	"SetContent\x12\x19.bridge.SetContentRequest\x1a\x10.bridge.Response\x12=\n" +
-	"\fResizeWindow\x12\x1b.bridge.ResizeWindowRequest\x1a\x10.bridge.Response\x12A\n" +
-	"\x0eSetWindowTitle\x12\x1d.bridge.SetWindowTitleRequest\x1a\x10.bridge.Response\x12=\n" +
-	"\fCenterWindow\x12\x1b.bridge.CenterWindowRequest\x1a\x10.bridge.Response\x12K\n" +
-	"\x13SetWindowFullScreen\x12\".bridge.SetWindowFullScreenRequest\x1a\x10.bridge.Response\x12;\n" +
-	"\vCreateImage\x12\x1a.bridge.CreateImageRequest\x1a\x10.bridge.Response\x12;\n" +
-	"\vCreateLabel\x12\x1a.bridge.CreateLabelRequest\x1a\x10.bridge.Response\x12=\n" +
-	"\fCreateButton\x12\x1b.bridge.CreateButtonRequest\x1a\x10.bridge.Response\x12;\n" +
-	"\vCreateEntry\x12\x1a.bridge.CreateEntryRequest\x1a\x10.bridge.Response\x129\n" +
-	"\n" +
-	"CreateVBox\x12\x19.bridge.CreateVBoxRequest\x1a\x10.bridge.Response\x129\n" +
-	"\n" +
-	"CreateHBox\x12\x19.bridge.CreateHBoxRequest\x1a\x10.bridge.Response\x12A\n" +
-	"\x0eCreateCheckbox\x12\x1d.bridge.CreateCheckboxRequest\x1a\x10.bridge.Response\x12=\n" +
-	"\fCreateSelect\x12\x1b.bridge.CreateSelectRequest\x1a\x10.bridge.Response\x12E\n" +
-	"\x10RegisterResource\x12\x1f.bridge.RegisterResourceRequest\x1a\x10.bridge.Response\x12I\n" +
-	"\x12UnregisterResource\x12!.bridge.UnregisterResourceRequest\x1a\x10.bridge.Response\x12;\n" +
-	"\vUpdateImage\x12\x1a.bridge.UpdateImageRequest\x1a\x10.bridge.Response\x123\n" +
-	"\aSetText\x12\x16.bridge.SetTextRequest\x1a\x10.bridge.Response\x12:\n" +
-	"\aGetText\x12\x16.bridge.GetTextRequest\x1a\x17.bridge.GetTextResponse\x12;\n" +
-	"\vSetProgress\x12\x1a.bridge.SetProgressRequest\x1a\x10.bridge.Response\x12F\n" +
-	"\vGetProgress\x12\x1a.bridge.GetProgressRequest\x1a\x1b.bridge.GetProgressResponse\x129\n" +
-	"\n" +
-	"SetChecked\x12\x19.bridge.SetCheckedRequest\x1a\x10.bridge.Response\x12C\n" +
-	"\n" +
-	"GetChecked\x12\x19.bridge.GetCheckedRequest\x1a\x1a.bridge.GetCheckedResponse\x12;\n" +
-	"\vClickWidget\x12\x1a.bridge.ClickWidgetRequest\x1a\x10.bridge.Response\x125\n" +
-	"\bTypeText\x12\x17.bridge.TypeTextRequest\x1a\x10.bridge.Response\x12C\n" +
-	"\x0fDoubleTapWidget\x12\x1e.bridge.DoubleTapWidgetRequest\x1a\x10.bridge.Response\x12E\n" +
-	"\x10RightClickWidget\x12\x1f.bridge.RightClickWidgetRequest\x1a\x10.bridge.Response\x129\n" +
-	"\n" +
-	"DragWidget\x12\x19.bridge.DragWidgetRequest\x1a\x10.bridge.Response\x12E\n" +
-	"\x10RegisterCustomId\x12\x1f.bridge.RegisterCustomIdRequest\x1a\x10.bridge.Response\x12C\n" +
-	"\n" +
-	"FindWidget\x12\x19.bridge.FindWidgetRequest\x1a\x1a.bridge.FindWidgetResponse\x12I\n" +
-	"\rGetWidgetInfo\x12\x1c.bridge.GetWidgetInfoRequest\x1a\x1a.bridge.WidgetInfoResponse\x12L\n" +
-	"\rGetAllWidgets\x12\x1c.bridge.GetAllWidgetsRequest\x1a\x1d.bridge.GetAllWidgetsResponse\x12=\n" +
-	"\x0fSubscribeEvents\x12\x19.bridge.EventSubscription\x1a\r.bridge.Event0\x01\x12-\n" +
-	"\x04Quit\x12\x13.bridge.QuitRequest\x1a\x10.bridge.ResponseB,Z*github.com/paul-hammant/tsyne/bridge/protob\x06proto3"
+// Generic dispatch
+type InvokeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                    // Correlation ID, echoed back in the response
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`                                // Message type as used over stdio, e.g. "createTabs"
+	Payload       string                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`                          // JSON-encoded payload object
+	DeadlineMs    int64                  `protobuf:"varint,4,opt,name=deadline_ms,json=deadlineMs,proto3" json:"deadline_ms,omitempty"` // Give up on the request after this long, 0 for none; the call's own deadline also applies
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-var (
-	file_proto_bridge_proto_rawDescOnce sync.Once
-	file_proto_bridge_proto_rawDescData []byte
-)
+func (x *InvokeRequest) Reset() {
+	*x = InvokeRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func file_proto_bridge_proto_rawDescGZIP() []byte {
-	file_proto_bridge_proto_rawDescOnce.Do(func() {
-		file_proto_bridge_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_bridge_proto_rawDesc), len(file_proto_bridge_proto_rawDesc)))
-	})
-	return file_proto_bridge_proto_rawDescData
+func (x *InvokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_proto_bridge_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
-var file_proto_bridge_proto_goTypes = []any{
-	(*Response)(nil),                   // 0: bridge.Response
-	(*CreateWindowRequest)(nil),        // 1: bridge.CreateWindowRequest
-	(*ShowWindowRequest)(nil),          // 2: bridge.ShowWindowRequest
-	(*SetContentRequest)(nil),          // 3: bridge.SetContentRequest
-	(*ResizeWindowRequest)(nil),        // 4: bridge.ResizeWindowRequest
+func (*InvokeRequest) ProtoMessage() {}
+
+func (x *InvokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeRequest.ProtoReflect.Descriptor instead.
+func (*InvokeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *InvokeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InvokeRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *InvokeRequest) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *InvokeRequest) GetDeadlineMs() int64 {
+	if x != nil {
+		return x.DeadlineMs
+	}
+	return 0
+}
+
+type InvokeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Result        string                 `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"` // JSON-encoded result object (empty if the handler returned none)
+	Code          string                 `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`     // machine-readable error code, e.g. MISSING_FIELD
+	Field         string                 `protobuf:"bytes,6,opt,name=field,proto3" json:"field,omitempty"`   // payload field the error is about
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeResponse) Reset() {
+	*x = InvokeResponse{}
+	mi := &file_proto_bridge_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeResponse) ProtoMessage() {}
+
+func (x *InvokeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeResponse.ProtoReflect.Descriptor instead.
+func (*InvokeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *InvokeResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InvokeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InvokeResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *InvokeResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *InvokeResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *InvokeResponse) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+// Session streaming
+type SessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*SessionRequest_Command
+	//	*SessionRequest_Subscribe
+	Kind          isSessionRequest_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SessionRequest) GetKind() isSessionRequest_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetCommand() *InvokeRequest {
+	if x != nil {
+		if x, ok := x.Kind.(*SessionRequest_Command); ok {
+			return x.Command
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetSubscribe() *EventSubscription {
+	if x != nil {
+		if x, ok := x.Kind.(*SessionRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+type isSessionRequest_Kind interface {
+	isSessionRequest_Kind()
+}
+
+type SessionRequest_Command struct {
+	Command *InvokeRequest `protobuf:"bytes,1,opt,name=command,proto3,oneof"` // Executed in the order received
+}
+
+type SessionRequest_Subscribe struct {
+	Subscribe *EventSubscription `protobuf:"bytes,2,opt,name=subscribe,proto3,oneof"` // Replaces the session's event subscription
+}
+
+func (*SessionRequest_Command) isSessionRequest_Kind() {}
+
+func (*SessionRequest_Subscribe) isSessionRequest_Kind() {}
+
+type SessionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*SessionResponse_Response
+	//	*SessionResponse_Event
+	Kind          isSessionResponse_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionResponse) Reset() {
+	*x = SessionResponse{}
+	mi := &file_proto_bridge_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionResponse) ProtoMessage() {}
+
+func (x *SessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionResponse.ProtoReflect.Descriptor instead.
+func (*SessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SessionResponse) GetKind() isSessionResponse_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetResponse() *InvokeResponse {
+	if x != nil {
+		if x, ok := x.Kind.(*SessionResponse_Response); ok {
+			return x.Response
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Kind.(*SessionResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+type isSessionResponse_Kind interface {
+	isSessionResponse_Kind()
+}
+
+type SessionResponse_Response struct {
+	Response *InvokeResponse `protobuf:"bytes,1,opt,name=response,proto3,oneof"` // Carries the ID of the command it answers
+}
+
+type SessionResponse_Event struct {
+	Event *Event `protobuf:"bytes,2,opt,name=event,proto3,oneof"`
+}
+
+func (*SessionResponse_Response) isSessionResponse_Kind() {}
+
+func (*SessionResponse_Event) isSessionResponse_Kind() {}
+
+// Token administration
+type CreateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scope         string                 `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`                              // "read", "interact" or "full"
+	TtlSeconds    int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // 0 for no expiry; never later than the caller's own token expires
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                                // label shown by ListTokens, e.g. "inspector"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTokenRequest) Reset() {
+	*x = CreateTokenRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTokenRequest) ProtoMessage() {}
+
+func (x *CreateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *CreateTokenRequest) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *CreateTokenRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateTokenRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type TokenInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`       // used to revoke the token
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"` // the secret to send as authorization metadata; only set by CreateToken
+	Scope         string                 `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix seconds, 0 for never
+	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenInfo) Reset() {
+	*x = TokenInfo{}
+	mi := &file_proto_bridge_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenInfo) ProtoMessage() {}
+
+func (x *TokenInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenInfo.ProtoReflect.Descriptor instead.
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TokenInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *TokenInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RevokeTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeTokenRequest) Reset() {
+	*x = RevokeTokenRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTokenRequest) ProtoMessage() {}
+
+func (x *RevokeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RevokeTokenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokensRequest) Reset() {
+	*x = ListTokensRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensRequest) ProtoMessage() {}
+
+func (x *ListTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListTokensRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{51}
+}
+
+type ListTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*TokenInfo           `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokensResponse) Reset() {
+	*x = ListTokensResponse{}
+	mi := &file_proto_bridge_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensResponse) ProtoMessage() {}
+
+func (x *ListTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListTokensResponse) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListTokensResponse) GetTokens() []*TokenInfo {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// Sessions
+type OpenSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // label shown by ListSessions, e.g. "settings page"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenSessionRequest) Reset() {
+	*x = OpenSessionRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSessionRequest) ProtoMessage() {}
+
+func (x *OpenSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSessionRequest.ProtoReflect.Descriptor instead.
+func (*OpenSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *OpenSessionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SessionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // send as "session" metadata to use the session
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix seconds
+	Windows       int32                  `protobuf:"varint,4,opt,name=windows,proto3" json:"windows,omitempty"`                      // windows the session has created
+	Widgets       int32                  `protobuf:"varint,5,opt,name=widgets,proto3" json:"widgets,omitempty"`                      // widgets the session has created
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionInfo) Reset() {
+	*x = SessionInfo{}
+	mi := &file_proto_bridge_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionInfo) ProtoMessage() {}
+
+func (x *SessionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SessionInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetWindows() int32 {
+	if x != nil {
+		return x.Windows
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetWidgets() int32 {
+	if x != nil {
+		return x.Widgets
+	}
+	return 0
+}
+
+type CloseSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseSessionRequest) Reset() {
+	*x = CloseSessionRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionRequest) ProtoMessage() {}
+
+func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
+func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CloseSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_proto_bridge_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{56}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionInfo         `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_proto_bridge_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_bridge_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_bridge_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*SessionInfo {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+var File_proto_bridge_proto protoreflect.FileDescriptor
+
+const file_proto_bridge_proto_rawDesc = "" +
+	"\n" +
+	"\x12proto/bridge.proto\x12\x06bridge\"\xd5\x01\n" +
+	"\bResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x124\n" +
+	"\x06result\x18\x03 \x03(\v2\x1c.bridge.Response.ResultEntryR\x06result\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12\x14\n" +
+	"\x05field\x18\x05 \x01(\tR\x05field\x1a9\n" +
+	"\vResultEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x95\x01\n" +
+	"\x13CreateWindowRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x05R\x06height\x12\x1d\n" +
+	"\n" +
+	"fixed_size\x18\x05 \x01(\bR\tfixedSize\"0\n" +
+	"\x11ShowWindowRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\"M\n" +
+	"\x11SetContentRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x1b\n" +
+	"\twidget_id\x18\x02 \x01(\tR\bwidgetId\"`\n" +
+	"\x13ResizeWindowRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\"J\n" +
+	"\x15SetWindowTitleRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"2\n" +
+	"\x13CenterWindowRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\"Y\n" +
+	"\x1aSetWindowFullScreenRequest\x12\x1b\n" +
+	"\twindow_id\x18\x01 \x01(\tR\bwindowId\x12\x1e\n" +
+	"\n" +
+	"fullscreen\x18\x02 \x01(\bR\n" +
+	"fullscreen\"\xb3\x02\n" +
+	"\x12CreateImageRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12!\n" +
+	"\vinline_data\x18\x02 \x01(\fH\x00R\n" +
+	"inlineData\x12%\n" +
+	"\rresource_name\x18\x03 \x01(\tH\x00R\fresourceName\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x05 \x01(\x05R\x06height\x12\x1f\n" +
+	"\vcallback_id\x18\x06 \x01(\tR\n" +
+	"callbackId\x12(\n" +
+	"\x10drag_callback_id\x18\a \x01(\tR\x0edragCallbackId\x123\n" +
+	"\x16double_tap_callback_id\x18\b \x01(\tR\x13doubleTapCallbackIdB\b\n" +
+	"\x06source\"w\n" +
+	"\x12CreateLabelRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x12\n" +
+	"\x04bold\x18\x03 \x01(\bR\x04bold\x12\x1c\n" +
+	"\talignment\x18\x04 \x01(\x05R\talignment\"\x85\x01\n" +
+	"\x13CreateButtonRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1f\n" +
+	"\vcallback_id\x18\x03 \x01(\tR\n" +
+	"callbackId\x12\x1c\n" +
+	"\timportant\x18\x04 \x01(\bR\timportant\"\xc4\x01\n" +
+	"\x12CreateEntryRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12 \n" +
+	"\vplaceholder\x18\x02 \x01(\tR\vplaceholder\x12\x1f\n" +
+	"\vcallback_id\x18\x03 \x01(\tR\n" +
+	"callbackId\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x1c\n" +
+	"\tmultiline\x18\x05 \x01(\bR\tmultiline\x12\x1a\n" +
+	"\bpassword\x18\x06 \x01(\bR\bpassword\"0\n" +
+	"\x11CreateVBoxRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"0\n" +
+	"\x11CreateHBoxRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"\x83\x01\n" +
+	"\x15CreateCheckboxRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x18\n" +
+	"\achecked\x18\x03 \x01(\bR\achecked\x12\x1f\n" +
+	"\vcallback_id\x18\x04 \x01(\tR\n" +
+	"callbackId\"\x89\x01\n" +
+	"\x13CreateSelectRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x18\n" +
+	"\aoptions\x18\x02 \x03(\tR\aoptions\x12\x1a\n" +
+	"\bselected\x18\x03 \x01(\x05R\bselected\x12\x1f\n" +
+	"\vcallback_id\x18\x04 \x01(\tR\n" +
+	"callbackId\"A\n" +
+	"\x17RegisterResourceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"/\n" +
+	"\x19UnregisterResourceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x85\x01\n" +
+	"\x12UpdateImageRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12!\n" +
+	"\vinline_data\x18\x02 \x01(\fH\x00R\n" +
+	"inlineData\x12%\n" +
+	"\rresource_name\x18\x03 \x01(\tH\x00R\fresourceNameB\b\n" +
+	"\x06source\"A\n" +
+	"\x0eSetTextRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"-\n" +
+	"\x0eGetTextRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"U\n" +
+	"\x0fGetTextResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"G\n" +
+	"\x12SetProgressRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"1\n" +
+	"\x12GetProgressRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"[\n" +
+	"\x13GetProgressResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"J\n" +
+	"\x11SetCheckedRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x18\n" +
+	"\achecked\x18\x02 \x01(\bR\achecked\"0\n" +
+	"\x11GetCheckedRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"^\n" +
+	"\x12GetCheckedResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x18\n" +
+	"\achecked\x18\x03 \x01(\bR\achecked\"1\n" +
+	"\x12ClickWidgetRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"B\n" +
+	"\x0fTypeTextRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"5\n" +
+	"\x16DoubleTapWidgetRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"6\n" +
+	"\x17RightClickWidgetRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"b\n" +
+	"\x11DragWidgetRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\x12\x17\n" +
+	"\adelta_x\x18\x02 \x01(\x05R\x06deltaX\x12\x17\n" +
+	"\adelta_y\x18\x03 \x01(\x05R\x06deltaY\"S\n" +
+	"\x17RegisterCustomIdRequest\x12\x1b\n" +
+	"\tcustom_id\x18\x01 \x01(\tR\bcustomId\x12\x1b\n" +
+	"\twidget_id\x18\x02 \x01(\tR\bwidgetId\"C\n" +
+	"\x11FindWidgetRequest\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"c\n" +
+	"\x12FindWidgetResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"widget_ids\x18\x03 \x03(\tR\twidgetIds\"3\n" +
+	"\x14GetWidgetInfoRequest\x12\x1b\n" +
+	"\twidget_id\x18\x01 \x01(\tR\bwidgetId\"\xfa\x01\n" +
+	"\x12WidgetInfoResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\f\n" +
+	"\x01x\x18\x06 \x01(\x02R\x01x\x12\f\n" +
+	"\x01y\x18\a \x01(\x02R\x01y\x12\x14\n" +
+	"\x05width\x18\b \x01(\x02R\x05width\x12\x16\n" +
+	"\x06height\x18\t \x01(\x02R\x06height\x12\x18\n" +
+	"\avisible\x18\n" +
+	" \x01(\bR\avisible\x12\x18\n" +
+	"\aenabled\x18\v \x01(\bR\aenabled\"\x16\n" +
+	"\x14GetAllWidgetsRequest\"u\n" +
+	"\x15GetAllWidgetsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12,\n" +
+	"\awidgets\x18\x03 \x03(\v2\x12.bridge.WidgetInfoR\awidgets\"D\n" +
+	"\n" +
+	"WidgetInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"\x9e\x01\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1b\n" +
+	"\twidget_id\x18\x02 \x01(\tR\bwidgetId\x12+\n" +
+	"\x04data\x18\x03 \x03(\v2\x17.bridge.Event.DataEntryR\x04data\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"4\n" +
+	"\x11EventSubscription\x12\x1f\n" +
+	"\vevent_types\x18\x01 \x03(\tR\n" +
+	"eventTypes\"\r\n" +
+	"\vQuitRequest\"n\n" +
+	"\rInvokeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\tR\apayload\x12\x1f\n" +
+	"\vdeadline_ms\x18\x04 \x01(\x03R\n" +
+	"deadlineMs\"\x92\x01\n" +
+	"\x0eInvokeResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\x12\x12\n" +
+	"\x04code\x18\x05 \x01(\tR\x04code\x12\x14\n" +
+	"\x05field\x18\x06 \x01(\tR\x05field\"\x86\x01\n" +
+	"\x0eSessionRequest\x121\n" +
+	"\acommand\x18\x01 \x01(\v2\x15.bridge.InvokeRequestH\x00R\acommand\x129\n" +
+	"\tsubscribe\x18\x02 \x01(\v2\x19.bridge.EventSubscriptionH\x00R\tsubscribeB\x06\n" +
+	"\x04kind\"v\n" +
+	"\x0fSessionResponse\x124\n" +
+	"\bresponse\x18\x01 \x01(\v2\x16.bridge.InvokeResponseH\x00R\bresponse\x12%\n" +
+	"\x05event\x18\x02 \x01(\v2\r.bridge.EventH\x00R\x05eventB\x06\n" +
+	"\x04kind\"_\n" +
+	"\x12CreateTokenRequest\x12\x14\n" +
+	"\x05scope\x18\x01 \x01(\tR\x05scope\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"z\n" +
+	"\tTokenInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x14\n" +
+	"\x05scope\x18\x03 \x01(\tR\x05scope\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\"$\n" +
+	"\x12RevokeTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x13\n" +
+	"\x11ListTokensRequest\"?\n" +
+	"\x12ListTokensResponse\x12)\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x11.bridge.TokenInfoR\x06tokens\"(\n" +
+	"\x12OpenSessionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x84\x01\n" +
+	"\vSessionInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x18\n" +
+	"\awindows\x18\x04 \x01(\x05R\awindows\x12\x18\n" +
+	"\awidgets\x18\x05 \x01(\x05R\awidgets\"%\n" +
+	"\x13CloseSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13ListSessionsRequest\"G\n" +
+	"\x14ListSessionsResponse\x12/\n" +
+	"\bsessions\x18\x01 \x03(\v2\x13.bridge.SessionInfoR\bsessions2\x99\x16\n" +
+	"\rBridgeService\x12=\n" +
+	"\fCreateWindow\x12\x1b.bridge.CreateWindowRequest\x1a\x10.bridge.Response\x129\n" +
+	"\n" +
+	"ShowWindow\x12\x19.bridge.ShowWindowRequest\x1a\x10.bridge.Response\x129\n" +
+	"\n" +
+	"SetContent\x12\x19.bridge.SetContentRequest\x1a\x10.bridge.Response\x12=\n" +
+	"\fResizeWindow\x12\x1b.bridge.ResizeWindowRequest\x1a\x10.bridge.Response\x12A\n" +
+	"\x0eSetWindowTitle\x12\x1d.bridge.SetWindowTitleRequest\x1a\x10.bridge.Response\x12=\n" +
+	"\fCenterWindow\x12\x1b.bridge.CenterWindowRequest\x1a\x10.bridge.Response\x12K\n" +
+	"\x13SetWindowFullScreen\x12\".bridge.SetWindowFullScreenRequest\x1a\x10.bridge.Response\x12;\n" +
+	"\vCreateImage\x12\x1a.bridge.CreateImageRequest\x1a\x10.bridge.Response\x12;\n" +
+	"\vCreateLabel\x12\x1a.bridge.CreateLabelRequest\x1a\x10.bridge.Response\x12=\n" +
+	"\fCreateButton\x12\x1b.bridge.CreateButtonRequest\x1a\x10.bridge.Response\x12;\n" +
+	"\vCreateEntry\x12\x1a.bridge.CreateEntryRequest\x1a\x10.bridge.Response\x129\n" +
+	"\n" +
+	"CreateVBox\x12\x19.bridge.CreateVBoxRequest\x1a\x10.bridge.Response\x129\n" +
+	"\n" +
+	"CreateHBox\x12\x19.bridge.CreateHBoxRequest\x1a\x10.bridge.Response\x12A\n" +
+	"\x0eCreateCheckbox\x12\x1d.bridge.CreateCheckboxRequest\x1a\x10.bridge.Response\x12=\n" +
+	"\fCreateSelect\x12\x1b.bridge.CreateSelectRequest\x1a\x10.bridge.Response\x12E\n" +
+	"\x10RegisterResource\x12\x1f.bridge.RegisterResourceRequest\x1a\x10.bridge.Response\x12I\n" +
+	"\x12UnregisterResource\x12!.bridge.UnregisterResourceRequest\x1a\x10.bridge.Response\x12;\n" +
+	"\vUpdateImage\x12\x1a.bridge.UpdateImageRequest\x1a\x10.bridge.Response\x123\n" +
+	"\aSetText\x12\x16.bridge.SetTextRequest\x1a\x10.bridge.Response\x12:\n" +
+	"\aGetText\x12\x16.bridge.GetTextRequest\x1a\x17.bridge.GetTextResponse\x12;\n" +
+	"\vSetProgress\x12\x1a.bridge.SetProgressRequest\x1a\x10.bridge.Response\x12F\n" +
+	"\vGetProgress\x12\x1a.bridge.GetProgressRequest\x1a\x1b.bridge.GetProgressResponse\x129\n" +
+	"\n" +
+	"SetChecked\x12\x19.bridge.SetCheckedRequest\x1a\x10.bridge.Response\x12C\n" +
+	"\n" +
+	"GetChecked\x12\x19.bridge.GetCheckedRequest\x1a\x1a.bridge.GetCheckedResponse\x12;\n" +
+	"\vClickWidget\x12\x1a.bridge.ClickWidgetRequest\x1a\x10.bridge.Response\x125\n" +
+	"\bTypeText\x12\x17.bridge.TypeTextRequest\x1a\x10.bridge.Response\x12C\n" +
+	"\x0fDoubleTapWidget\x12\x1e.bridge.DoubleTapWidgetRequest\x1a\x10.bridge.Response\x12E\n" +
+	"\x10RightClickWidget\x12\x1f.bridge.RightClickWidgetRequest\x1a\x10.bridge.Response\x129\n" +
+	"\n" +
+	"DragWidget\x12\x19.bridge.DragWidgetRequest\x1a\x10.bridge.Response\x12E\n" +
+	"\x10RegisterCustomId\x12\x1f.bridge.RegisterCustomIdRequest\x1a\x10.bridge.Response\x12C\n" +
+	"\n" +
+	"FindWidget\x12\x19.bridge.FindWidgetRequest\x1a\x1a.bridge.FindWidgetResponse\x12I\n" +
+	"\rGetWidgetInfo\x12\x1c.bridge.GetWidgetInfoRequest\x1a\x1a.bridge.WidgetInfoResponse\x12L\n" +
+	"\rGetAllWidgets\x12\x1c.bridge.GetAllWidgetsRequest\x1a\x1d.bridge.GetAllWidgetsResponse\x12=\n" +
+	"\x0fSubscribeEvents\x12\x19.bridge.EventSubscription\x1a\r.bridge.Event0\x01\x12-\n" +
+	"\x04Quit\x12\x13.bridge.QuitRequest\x1a\x10.bridge.Response\x127\n" +
+	"\x06Invoke\x12\x15.bridge.InvokeRequest\x1a\x16.bridge.InvokeResponse\x12A\n" +
+	"\fInvokeStream\x12\x15.bridge.InvokeRequest\x1a\x16.bridge.InvokeResponse(\x010\x01\x12>\n" +
+	"\aSession\x12\x16.bridge.SessionRequest\x1a\x17.bridge.SessionResponse(\x010\x01\x12<\n" +
+	"\vCreateToken\x12\x1a.bridge.CreateTokenRequest\x1a\x11.bridge.TokenInfo\x12;\n" +
+	"\vRevokeToken\x12\x1a.bridge.RevokeTokenRequest\x1a\x10.bridge.Response\x12C\n" +
+	"\n" +
+	"ListTokens\x12\x19.bridge.ListTokensRequest\x1a\x1a.bridge.ListTokensResponse\x12>\n" +
+	"\vOpenSession\x12\x1a.bridge.OpenSessionRequest\x1a\x13.bridge.SessionInfo\x12=\n" +
+	"\fCloseSession\x12\x1b.bridge.CloseSessionRequest\x1a\x10.bridge.Response\x12I\n" +
+	"\fListSessions\x12\x1b.bridge.ListSessionsRequest\x1a\x1c.bridge.ListSessionsResponseB,Z*github.com/paul-hammant/tsyne/bridge/protob\x06proto3"
+
+var (
+	file_proto_bridge_proto_rawDescOnce sync.Once
+	file_proto_bridge_proto_rawDescData []byte
+)
+
+func file_proto_bridge_proto_rawDescGZIP() []byte {
+	file_proto_bridge_proto_rawDescOnce.Do(func() {
+		file_proto_bridge_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_bridge_proto_rawDesc), len(file_proto_bridge_proto_rawDesc)))
+	})
+	return file_proto_bridge_proto_rawDescData
+}
+
+var file_proto_bridge_proto_msgTypes = make([]protoimpl.MessageInfo, 60)
+var file_proto_bridge_proto_goTypes = []any{
+	(*Response)(nil),                   // 0: bridge.Response
+	(*CreateWindowRequest)(nil),        // 1: bridge.CreateWindowRequest
+	(*ShowWindowRequest)(nil),          // 2: bridge.ShowWindowRequest
+	(*SetContentRequest)(nil),          // 3: bridge.SetContentRequest
+	(*ResizeWindowRequest)(nil),        // 4: bridge.ResizeWindowRequest
 	(*SetWindowTitleRequest)(nil),      // 5: bridge.SetWindowTitleRequest
 	(*CenterWindowRequest)(nil),        // 6: bridge.CenterWindowRequest
 	(*SetWindowFullScreenRequest)(nil), // 7: bridge.SetWindowFullScreenRequest
@@ -2857,88 +3761,126 @@ var file_proto_bridge_proto_goTypes = []any{
 	(*Event)(nil),                      // 41: bridge.Event
 	(*EventSubscription)(nil),          // 42: bridge.EventSubscription
 	(*QuitRequest)(nil),                // 43: bridge.QuitRequest
-	nil,                                // 44: bridge.Response.ResultEntry
-	nil,                                // 45: bridge.Event.DataEntry
+	(*InvokeRequest)(nil),              // 44: bridge.InvokeRequest
+	(*InvokeResponse)(nil),             // 45: bridge.InvokeResponse
+	(*SessionRequest)(nil),             // 46: bridge.SessionRequest
+	(*SessionResponse)(nil),            // 47: bridge.SessionResponse
+	(*CreateTokenRequest)(nil),         // 48: bridge.CreateTokenRequest
+	(*TokenInfo)(nil),                  // 49: bridge.TokenInfo
+	(*RevokeTokenRequest)(nil),         // 50: bridge.RevokeTokenRequest
+	(*ListTokensRequest)(nil),          // 51: bridge.ListTokensRequest
+	(*ListTokensResponse)(nil),         // 52: bridge.ListTokensResponse
+	(*OpenSessionRequest)(nil),         // 53: bridge.OpenSessionRequest
+	(*SessionInfo)(nil),                // 54: bridge.SessionInfo
+	(*CloseSessionRequest)(nil),        // 55: bridge.CloseSessionRequest
+	(*ListSessionsRequest)(nil),        // 56: bridge.ListSessionsRequest
+	(*ListSessionsResponse)(nil),       // 57: bridge.ListSessionsResponse
+	nil,                                // 58: bridge.Response.ResultEntry
+	nil,                                // 59: bridge.Event.DataEntry
 }
 var file_proto_bridge_proto_depIdxs = []int32{
-	44, // 0: bridge.Response.result:type_name -> bridge.Response.ResultEntry
+	58, // 0: bridge.Response.result:type_name -> bridge.Response.ResultEntry
 	40, // 1: bridge.GetAllWidgetsResponse.widgets:type_name -> bridge.WidgetInfo
-	45, // 2: bridge.Event.data:type_name -> bridge.Event.DataEntry
-	1,  // 3: bridge.BridgeService.CreateWindow:input_type -> bridge.CreateWindowRequest
-	2,  // 4: bridge.BridgeService.ShowWindow:input_type -> bridge.ShowWindowRequest
-	3,  // 5: bridge.BridgeService.SetContent:input_type -> bridge.SetContentRequest
-	4,  // 6: bridge.BridgeService.ResizeWindow:input_type -> bridge.ResizeWindowRequest
-	5,  // 7: bridge.BridgeService.SetWindowTitle:input_type -> bridge.SetWindowTitleRequest
-	6,  // 8: bridge.BridgeService.CenterWindow:input_type -> bridge.CenterWindowRequest
-	7,  // 9: bridge.BridgeService.SetWindowFullScreen:input_type -> bridge.SetWindowFullScreenRequest
-	8,  // 10: bridge.BridgeService.CreateImage:input_type -> bridge.CreateImageRequest
-	9,  // 11: bridge.BridgeService.CreateLabel:input_type -> bridge.CreateLabelRequest
-	10, // 12: bridge.BridgeService.CreateButton:input_type -> bridge.CreateButtonRequest
-	11, // 13: bridge.BridgeService.CreateEntry:input_type -> bridge.CreateEntryRequest
-	12, // 14: bridge.BridgeService.CreateVBox:input_type -> bridge.CreateVBoxRequest
-	13, // 15: bridge.BridgeService.CreateHBox:input_type -> bridge.CreateHBoxRequest
-	14, // 16: bridge.BridgeService.CreateCheckbox:input_type -> bridge.CreateCheckboxRequest
-	15, // 17: bridge.BridgeService.CreateSelect:input_type -> bridge.CreateSelectRequest
-	16, // 18: bridge.BridgeService.RegisterResource:input_type -> bridge.RegisterResourceRequest
-	17, // 19: bridge.BridgeService.UnregisterResource:input_type -> bridge.UnregisterResourceRequest
-	18, // 20: bridge.BridgeService.UpdateImage:input_type -> bridge.UpdateImageRequest
-	19, // 21: bridge.BridgeService.SetText:input_type -> bridge.SetTextRequest
-	20, // 22: bridge.BridgeService.GetText:input_type -> bridge.GetTextRequest
-	22, // 23: bridge.BridgeService.SetProgress:input_type -> bridge.SetProgressRequest
-	23, // 24: bridge.BridgeService.GetProgress:input_type -> bridge.GetProgressRequest
-	25, // 25: bridge.BridgeService.SetChecked:input_type -> bridge.SetCheckedRequest
-	26, // 26: bridge.BridgeService.GetChecked:input_type -> bridge.GetCheckedRequest
-	28, // 27: bridge.BridgeService.ClickWidget:input_type -> bridge.ClickWidgetRequest
-	29, // 28: bridge.BridgeService.TypeText:input_type -> bridge.TypeTextRequest
-	30, // 29: bridge.BridgeService.DoubleTapWidget:input_type -> bridge.DoubleTapWidgetRequest
-	31, // 30: bridge.BridgeService.RightClickWidget:input_type -> bridge.RightClickWidgetRequest
-	32, // 31: bridge.BridgeService.DragWidget:input_type -> bridge.DragWidgetRequest
-	33, // 32: bridge.BridgeService.RegisterCustomId:input_type -> bridge.RegisterCustomIdRequest
-	34, // 33: bridge.BridgeService.FindWidget:input_type -> bridge.FindWidgetRequest
-	36, // 34: bridge.BridgeService.GetWidgetInfo:input_type -> bridge.GetWidgetInfoRequest
-	38, // 35: bridge.BridgeService.GetAllWidgets:input_type -> bridge.GetAllWidgetsRequest
-	42, // 36: bridge.BridgeService.SubscribeEvents:input_type -> bridge.EventSubscription
-	43, // 37: bridge.BridgeService.Quit:input_type -> bridge.QuitRequest
-	0,  // 38: bridge.BridgeService.CreateWindow:output_type -> bridge.Response
-	0,  // 39: bridge.BridgeService.ShowWindow:output_type -> bridge.Response
-	0,  // 40: bridge.BridgeService.SetContent:output_type -> bridge.Response
-	0,  // 41: bridge.BridgeService.ResizeWindow:output_type -> bridge.Response
-	0,  // 42: bridge.BridgeService.SetWindowTitle:output_type -> bridge.Response
-	0,  // 43: bridge.BridgeService.CenterWindow:output_type -> bridge.Response
-	0,  // 44: bridge.BridgeService.SetWindowFullScreen:output_type -> bridge.Response
-	0,  // 45: bridge.BridgeService.CreateImage:output_type -> bridge.Response
-	0,  // 46: bridge.BridgeService.CreateLabel:output_type -> bridge.Response
-	0,  // 47: bridge.BridgeService.CreateButton:output_type -> bridge.Response
-	0,  // 48: bridge.BridgeService.CreateEntry:output_type -> bridge.Response
-	0,  // 49: bridge.BridgeService.CreateVBox:output_type -> bridge.Response
-	0,  // 50: bridge.BridgeService.CreateHBox:output_type -> bridge.Response
-	0,  // 51: bridge.BridgeService.CreateCheckbox:output_type -> bridge.Response
-	0,  // 52: bridge.BridgeService.CreateSelect:output_type -> bridge.Response
-	0,  // 53: bridge.BridgeService.RegisterResource:output_type -> bridge.Response
-	0,  // 54: bridge.BridgeService.UnregisterResource:output_type -> bridge.Response
-	0,  // 55: bridge.BridgeService.UpdateImage:output_type -> bridge.Response
-	0,  // 56: bridge.BridgeService.SetText:output_type -> bridge.Response
-	21, // 57: bridge.BridgeService.GetText:output_type -> bridge.GetTextResponse
-	0,  // 58: bridge.BridgeService.SetProgress:output_type -> bridge.Response
-	24, // 59: bridge.BridgeService.GetProgress:output_type -> bridge.GetProgressResponse
-	0,  // 60: bridge.BridgeService.SetChecked:output_type -> bridge.Response
-	27, // 61: bridge.BridgeService.GetChecked:output_type -> bridge.GetCheckedResponse
-	0,  // 62: bridge.BridgeService.ClickWidget:output_type -> bridge.Response
-	0,  // 63: bridge.BridgeService.TypeText:output_type -> bridge.Response
-	0,  // 64: bridge.BridgeService.DoubleTapWidget:output_type -> bridge.Response
-	0,  // 65: bridge.BridgeService.RightClickWidget:output_type -> bridge.Response
-	0,  // 66: bridge.BridgeService.DragWidget:output_type -> bridge.Response
-	0,  // 67: bridge.BridgeService.RegisterCustomId:output_type -> bridge.Response
-	35, // 68: bridge.BridgeService.FindWidget:output_type -> bridge.FindWidgetResponse
-	37, // 69: bridge.BridgeService.GetWidgetInfo:output_type -> bridge.WidgetInfoResponse
-	39, // 70: bridge.BridgeService.GetAllWidgets:output_type -> bridge.GetAllWidgetsResponse
-	41, // 71: bridge.BridgeService.SubscribeEvents:output_type -> bridge.Event
-	0,  // 72: bridge.BridgeService.Quit:output_type -> bridge.Response
-	38, // [38:73] is the sub-list for method output_type
-	3,  // [3:38] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	59, // 2: bridge.Event.data:type_name -> bridge.Event.DataEntry
+	44, // 3: bridge.SessionRequest.command:type_name -> bridge.InvokeRequest
+	42, // 4: bridge.SessionRequest.subscribe:type_name -> bridge.EventSubscription
+	45, // 5: bridge.SessionResponse.response:type_name -> bridge.InvokeResponse
+	41, // 6: bridge.SessionResponse.event:type_name -> bridge.Event
+	49, // 7: bridge.ListTokensResponse.tokens:type_name -> bridge.TokenInfo
+	54, // 8: bridge.ListSessionsResponse.sessions:type_name -> bridge.SessionInfo
+	1,  // 9: bridge.BridgeService.CreateWindow:input_type -> bridge.CreateWindowRequest
+	2,  // 10: bridge.BridgeService.ShowWindow:input_type -> bridge.ShowWindowRequest
+	3,  // 11: bridge.BridgeService.SetContent:input_type -> bridge.SetContentRequest
+	4,  // 12: bridge.BridgeService.ResizeWindow:input_type -> bridge.ResizeWindowRequest
+	5,  // 13: bridge.BridgeService.SetWindowTitle:input_type -> bridge.SetWindowTitleRequest
+	6,  // 14: bridge.BridgeService.CenterWindow:input_type -> bridge.CenterWindowRequest
+	7,  // 15: bridge.BridgeService.SetWindowFullScreen:input_type -> bridge.SetWindowFullScreenRequest
+	8,  // 16: bridge.BridgeService.CreateImage:input_type -> bridge.CreateImageRequest
+	9,  // 17: bridge.BridgeService.CreateLabel:input_type -> bridge.CreateLabelRequest
+	10, // 18: bridge.BridgeService.CreateButton:input_type -> bridge.CreateButtonRequest
+	11, // 19: bridge.BridgeService.CreateEntry:input_type -> bridge.CreateEntryRequest
+	12, // 20: bridge.BridgeService.CreateVBox:input_type -> bridge.CreateVBoxRequest
+	13, // 21: bridge.BridgeService.CreateHBox:input_type -> bridge.CreateHBoxRequest
+	14, // 22: bridge.BridgeService.CreateCheckbox:input_type -> bridge.CreateCheckboxRequest
+	15, // 23: bridge.BridgeService.CreateSelect:input_type -> bridge.CreateSelectRequest
+	16, // 24: bridge.BridgeService.RegisterResource:input_type -> bridge.RegisterResourceRequest
+	17, // 25: bridge.BridgeService.UnregisterResource:input_type -> bridge.UnregisterResourceRequest
+	18, // 26: bridge.BridgeService.UpdateImage:input_type -> bridge.UpdateImageRequest
+	19, // 27: bridge.BridgeService.SetText:input_type -> bridge.SetTextRequest
+	20, // 28: bridge.BridgeService.GetText:input_type -> bridge.GetTextRequest
+	22, // 29: bridge.BridgeService.SetProgress:input_type -> bridge.SetProgressRequest
+	23, // 30: bridge.BridgeService.GetProgress:input_type -> bridge.GetProgressRequest
+	25, // 31: bridge.BridgeService.SetChecked:input_type -> bridge.SetCheckedRequest
+	26, // 32: bridge.BridgeService.GetChecked:input_type -> bridge.GetCheckedRequest
+	28, // 33: bridge.BridgeService.ClickWidget:input_type -> bridge.ClickWidgetRequest
+	29, // 34: bridge.BridgeService.TypeText:input_type -> bridge.TypeTextRequest
+	30, // 35: bridge.BridgeService.DoubleTapWidget:input_type -> bridge.DoubleTapWidgetRequest
+	31, // 36: bridge.BridgeService.RightClickWidget:input_type -> bridge.RightClickWidgetRequest
+	32, // 37: bridge.BridgeService.DragWidget:input_type -> bridge.DragWidgetRequest
+	33, // 38: bridge.BridgeService.RegisterCustomId:input_type -> bridge.RegisterCustomIdRequest
+	34, // 39: bridge.BridgeService.FindWidget:input_type -> bridge.FindWidgetRequest
+	36, // 40: bridge.BridgeService.GetWidgetInfo:input_type -> bridge.GetWidgetInfoRequest
+	38, // 41: bridge.BridgeService.GetAllWidgets:input_type -> bridge.GetAllWidgetsRequest
+	42, // 42: bridge.BridgeService.SubscribeEvents:input_type -> bridge.EventSubscription
+	43, // 43: bridge.BridgeService.Quit:input_type -> bridge.QuitRequest
+	44, // 44: bridge.BridgeService.Invoke:input_type -> bridge.InvokeRequest
+	44, // 45: bridge.BridgeService.InvokeStream:input_type -> bridge.InvokeRequest
+	46, // 46: bridge.BridgeService.Session:input_type -> bridge.SessionRequest
+	48, // 47: bridge.BridgeService.CreateToken:input_type -> bridge.CreateTokenRequest
+	50, // 48: bridge.BridgeService.RevokeToken:input_type -> bridge.RevokeTokenRequest
+	51, // 49: bridge.BridgeService.ListTokens:input_type -> bridge.ListTokensRequest
+	53, // 50: bridge.BridgeService.OpenSession:input_type -> bridge.OpenSessionRequest
+	55, // 51: bridge.BridgeService.CloseSession:input_type -> bridge.CloseSessionRequest
+	56, // 52: bridge.BridgeService.ListSessions:input_type -> bridge.ListSessionsRequest
+	0,  // 53: bridge.BridgeService.CreateWindow:output_type -> bridge.Response
+	0,  // 54: bridge.BridgeService.ShowWindow:output_type -> bridge.Response
+	0,  // 55: bridge.BridgeService.SetContent:output_type -> bridge.Response
+	0,  // 56: bridge.BridgeService.ResizeWindow:output_type -> bridge.Response
+	0,  // 57: bridge.BridgeService.SetWindowTitle:output_type -> bridge.Response
+	0,  // 58: bridge.BridgeService.CenterWindow:output_type -> bridge.Response
+	0,  // 59: bridge.BridgeService.SetWindowFullScreen:output_type -> bridge.Response
+	0,  // 60: bridge.BridgeService.CreateImage:output_type -> bridge.Response
+	0,  // 61: bridge.BridgeService.CreateLabel:output_type -> bridge.Response
+	0,  // 62: bridge.BridgeService.CreateButton:output_type -> bridge.Response
+	0,  // 63: bridge.BridgeService.CreateEntry:output_type -> bridge.Response
+	0,  // 64: bridge.BridgeService.CreateVBox:output_type -> bridge.Response
+	0,  // 65: bridge.BridgeService.CreateHBox:output_type -> bridge.Response
+	0,  // 66: bridge.BridgeService.CreateCheckbox:output_type -> bridge.Response
+	0,  // 67: bridge.BridgeService.CreateSelect:output_type -> bridge.Response
+	0,  // 68: bridge.BridgeService.RegisterResource:output_type -> bridge.Response
+	0,  // 69: bridge.BridgeService.UnregisterResource:output_type -> bridge.Response
+	0,  // 70: bridge.BridgeService.UpdateImage:output_type -> bridge.Response
+	0,  // 71: bridge.BridgeService.SetText:output_type -> bridge.Response
+	21, // 72: bridge.BridgeService.GetText:output_type -> bridge.GetTextResponse
+	0,  // 73: bridge.BridgeService.SetProgress:output_type -> bridge.Response
+	24, // 74: bridge.BridgeService.GetProgress:output_type -> bridge.GetProgressResponse
+	0,  // 75: bridge.BridgeService.SetChecked:output_type -> bridge.Response
+	27, // 76: bridge.BridgeService.GetChecked:output_type -> bridge.GetCheckedResponse
+	0,  // 77: bridge.BridgeService.ClickWidget:output_type -> bridge.Response
+	0,  // 78: bridge.BridgeService.TypeText:output_type -> bridge.Response
+	0,  // 79: bridge.BridgeService.DoubleTapWidget:output_type -> bridge.Response
+	0,  // 80: bridge.BridgeService.RightClickWidget:output_type -> bridge.Response
+	0,  // 81: bridge.BridgeService.DragWidget:output_type -> bridge.Response
+	0,  // 82: bridge.BridgeService.RegisterCustomId:output_type -> bridge.Response
+	35, // 83: bridge.BridgeService.FindWidget:output_type -> bridge.FindWidgetResponse
+	37, // 84: bridge.BridgeService.GetWidgetInfo:output_type -> bridge.WidgetInfoResponse
+	39, // 85: bridge.BridgeService.GetAllWidgets:output_type -> bridge.GetAllWidgetsResponse
+	41, // 86: bridge.BridgeService.SubscribeEvents:output_type -> bridge.Event
+	0,  // 87: bridge.BridgeService.Quit:output_type -> bridge.Response
+	45, // 88: bridge.BridgeService.Invoke:output_type -> bridge.InvokeResponse
+	45, // 89: bridge.BridgeService.InvokeStream:output_type -> bridge.InvokeResponse
+	47, // 90: bridge.BridgeService.Session:output_type -> bridge.SessionResponse
+	49, // 91: bridge.BridgeService.CreateToken:output_type -> bridge.TokenInfo
+	0,  // 92: bridge.BridgeService.RevokeToken:output_type -> bridge.Response
+	52, // 93: bridge.BridgeService.ListTokens:output_type -> bridge.ListTokensResponse
+	54, // 94: bridge.BridgeService.OpenSession:output_type -> bridge.SessionInfo
+	0,  // 95: bridge.BridgeService.CloseSession:output_type -> bridge.Response
+	57, // 96: bridge.BridgeService.ListSessions:output_type -> bridge.ListSessionsResponse
+	53, // [53:97] is the sub-list for method output_type
+	9,  // [9:53] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_bridge_proto_init() }
@@ -2954,13 +3896,21 @@ func file_proto_bridge_proto_init() {
 		(*UpdateImageRequest_InlineData)(nil),
 		(*UpdateImageRequest_ResourceName)(nil),
 	}
+	file_proto_bridge_proto_msgTypes[46].OneofWrappers = []any{
+		(*SessionRequest_Command)(nil),
+		(*SessionRequest_Subscribe)(nil),
+	}
+	file_proto_bridge_proto_msgTypes[47].OneofWrappers = []any{
+		(*SessionResponse_Response)(nil),
+		(*SessionResponse_Event)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_bridge_proto_rawDesc), len(file_proto_bridge_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   46,
+			NumMessages:   60,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
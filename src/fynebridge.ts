@@ -6,6 +6,14 @@ export interface Message {
   id: string;
   type: string;
   payload: Record<string, any>;
+  deadlineMs?: number;
+}
+
+export interface SendOptions {
+  /** The bridge gives up and rejects with code DEADLINE_EXCEEDED after this long */
+  deadlineMs?: number;
+  /** Aborting sends a cancel message; the request rejects with code CANCELLED */
+  signal?: AbortSignal;
 }
 
 export interface Response {
@@ -13,6 +21,18 @@ export interface Response {
   success: boolean;
   result?: Record<string, any>;
   error?: string;
+  code?: string;   // machine-readable error code, e.g. MISSING_FIELD or WIDGET_NOT_FOUND
+  field?: string;  // payload field the error is about
+}
+
+/**
+ * A request the bridge rejected, with the bridge's error code and offending field
+ */
+export class BridgeError extends Error {
+  constructor(message: string, public readonly code?: string, public readonly field?: string) {
+    super(message);
+    this.name = 'BridgeError';
+  }
 }
 
 export interface Event {
@@ -21,6 +41,31 @@ export interface Event {
   data?: Record<string, any>;
 }
 
+// Every frame starts with this marker so a reader can resynchronise after corrupted input.
+// 0xFF and 0xFE never occur in UTF-8 text, so the marker cannot appear inside a JSON payload.
+const FRAME_MAGIC = Buffer.from([0xff, 0xfe, 0x54, 0x53]); // 0xFF 0xFE 'T' 'S'
+const FRAME_HEADER_SIZE = 12; // magic + length + crc32
+
+/**
+ * The bridge protocol this client speaks, as "major.minor". It follows
+ * protocolVersion in bridge/core/handshake.go.
+ */
+export const PROTOCOL_VERSION = '2.9';
+
+/**
+ * Whether a bridge speaking the given version can serve this client: the major
+ * versions must match, and the bridge must provide at least our minor version
+ */
+function protocolCompatible(bridgeVersion: string): boolean {
+  const [clientMajor, clientMinor] = PROTOCOL_VERSION.split('.').map(Number);
+  const parts = bridgeVersion.split('.');
+  if (parts.length !== 2) {
+    return false;
+  }
+  const [bridgeMajor, bridgeMinor] = parts.map(Number);
+  return bridgeMajor === clientMajor && bridgeMinor >= clientMinor;
+}
+
 export class BridgeConnection {
   private process: ChildProcess;
   private messageId = 0;
@@ -31,6 +76,7 @@ export class BridgeConnection {
   private eventHandlers = new Map<string, (data: any) => void>();
   private readyPromise: Promise<void>;
   private readyResolve?: () => void;
+  private readyReject?: (error: Error) => void;
   private buffer = Buffer.alloc(0); // Accumulate incoming data
   private readingFrame = false;
   private quitTimeout?: NodeJS.Timeout;
@@ -59,13 +105,16 @@ export class BridgeConnection {
       stdio: ['pipe', 'pipe', 'inherit']
     });
 
-    // Create promise that resolves when bridge is ready
-    this.readyPromise = new Promise((resolve) => {
+    // Create promise that resolves when bridge is ready and has accepted our protocol version
+    this.readyPromise = new Promise((resolve, reject) => {
       this.readyResolve = resolve;
+      this.readyReject = reject;
     });
+    // Callers see the rejection when they wait; this only stops it being reported as unhandled
+    this.readyPromise.catch(() => {});
 
     // IPC Safeguard: Read framed messages with length-prefix and CRC32 validation
-    // Frame format: [uint32 length][uint32 crc32][json bytes]
+    // Frame format: [magic][uint32 length][uint32 crc32][json bytes]
     this.process.stdout!.on('data', (chunk: Buffer) => {
       // Accumulate data in buffer
       this.buffer = Buffer.concat([this.buffer, chunk]);
@@ -93,41 +142,57 @@ export class BridgeConnection {
    * Returns true if a message was read, false if more data is needed
    */
   private tryReadFrame(): boolean {
-    // Need at least 8 bytes for length + crc32
-    if (this.buffer.length < 8) {
+    // Resynchronise: discard anything before the next frame marker
+    const markerAt = this.buffer.indexOf(FRAME_MAGIC);
+    if (markerAt < 0) {
+      if (this.buffer.length >= FRAME_MAGIC.length) {
+        // Keep a tail that may be the start of a marker split across chunks
+        const keep = FRAME_MAGIC.length - 1;
+        console.error(`Discarding ${this.buffer.length - keep} bytes without a frame marker`);
+        this.buffer = this.buffer.slice(this.buffer.length - keep);
+      }
+      return false;
+    }
+    if (markerAt > 0) {
+      console.error(`Skipping ${markerAt} bytes before frame marker`);
+      this.buffer = this.buffer.slice(markerAt);
+    }
+
+    // Need at least 12 bytes for magic + length + crc32
+    if (this.buffer.length < FRAME_HEADER_SIZE) {
       return false;
     }
 
     // Read length prefix (4 bytes, big-endian)
-    const length = this.buffer.readUInt32BE(0);
+    const length = this.buffer.readUInt32BE(4);
 
     // Sanity check: reject unreasonably large messages (> 10MB)
     if (length > 10 * 1024 * 1024) {
       console.error(`Message too large: ${length} bytes`);
-      // Skip the corrupt frame by discarding buffer
-      this.buffer = Buffer.alloc(0);
-      return false;
+      // The length is garbage: step past this marker and rescan for the next frame
+      this.buffer = this.buffer.slice(1);
+      return true;
     }
 
     // Check if we have the complete frame
-    const frameSize = 8 + length; // 4 bytes length + 4 bytes crc32 + payload
+    const frameSize = FRAME_HEADER_SIZE + length;
     if (this.buffer.length < frameSize) {
       return false; // Wait for more data
     }
 
     // Read CRC32 checksum (4 bytes, big-endian)
-    const expectedChecksum = this.buffer.readUInt32BE(4);
+    const expectedChecksum = this.buffer.readUInt32BE(8);
 
     // Read JSON payload
-    const payload = this.buffer.slice(8, 8 + length);
+    const payload = this.buffer.slice(FRAME_HEADER_SIZE, frameSize);
 
     // Validate CRC32 checksum
     const actualChecksum = crc32.unsigned(payload);
     if (actualChecksum !== expectedChecksum) {
       console.error(`Checksum mismatch: expected ${expectedChecksum}, got ${actualChecksum}`);
-      // Try to recover by skipping this frame
-      this.buffer = this.buffer.slice(frameSize);
-      return false;
+      // Step past this marker and rescan: the next frame may start inside the bad one
+      this.buffer = this.buffer.slice(1);
+      return true;
     }
 
     // Parse JSON message
@@ -158,7 +223,14 @@ export class BridgeConnection {
   private handleResponse(response: Response): void {
     // Handle ready signal
     if (response.id === 'ready' && this.readyResolve) {
-      this.readyResolve();
+      const resolve = this.readyResolve;
+      const reject = this.readyReject!;
+      this.readyResolve = undefined;
+      this.handshake(response.result).then(resolve, (err) => {
+        console.error(`Bridge handshake failed: ${err.message}`);
+        reject(err);
+        this.shutdown();
+      });
       return;
     }
 
@@ -168,12 +240,35 @@ export class BridgeConnection {
       if (response.success) {
         pending.resolve(response.result || {});
       } else {
-        pending.reject(new Error(response.error || 'Unknown error'));
+        pending.reject(new BridgeError(response.error || 'Unknown error', response.code, response.field));
       }
     }
   }
 
   private handleEvent(event: Event): void {
+    if (event.type === 'protocolError') {
+      // The bridge skipped corrupted input: fail the requests it lost rather than wait forever
+      console.error(`Bridge protocol error: ${event.data?.reason}`);
+      for (const id of event.data?.lostIds || []) {
+        const pending = this.pendingRequests.get(id);
+        if (pending) {
+          this.pendingRequests.delete(id);
+          pending.reject(new Error(`Request lost in transit: ${event.data?.reason}`));
+        }
+      }
+      return;
+    }
+
+    if (event.type === 'bridgeError') {
+      // A handler or callback panicked; the bridge recovered and keeps running
+      console.error(`Bridge error in ${event.data?.source}: ${event.data?.error}`);
+    }
+
+    if (event.type === 'eventsDropped') {
+      // We fell behind and the bridge dropped events rather than block its UI thread
+      console.warn(`Bridge dropped ${event.data?.count} events because the client fell behind`);
+    }
+
     if (event.type === 'callback' && event.data?.callbackId) {
       const handler = this.eventHandlers.get(event.data.callbackId);
       if (handler) {
@@ -198,6 +293,19 @@ export class BridgeConnection {
     }
   }
 
+  /**
+   * Check the bridge's protocol version, then tell it ours with hello, before
+   * any command is sent. Rejects if the two cannot talk to each other.
+   */
+  private async handshake(ready: Record<string, any> = {}): Promise<void> {
+    const bridgeVersion = ready.protocolVersion;
+    if (bridgeVersion && !protocolCompatible(bridgeVersion)) {
+      throw new BridgeError(
+        `incompatible protocol version: client speaks ${PROTOCOL_VERSION}, bridge speaks ${bridgeVersion}`);
+    }
+    await this.request('hello', { protocolVersion: PROTOCOL_VERSION });
+  }
+
   /**
    * Wait for the bridge to be ready to receive commands
    */
@@ -205,26 +313,43 @@ export class BridgeConnection {
     return this.readyPromise;
   }
 
-  async send(type: string, payload: Record<string, any>): Promise<any> {
+  async send(type: string, payload: Record<string, any>, options: SendOptions = {}): Promise<any> {
     // Wait for bridge to be ready before sending commands
     await this.readyPromise;
+    return this.request(type, payload, options);
+  }
 
+  /**
+   * Write a message without waiting for the bridge to be ready
+   */
+  private request(type: string, payload: Record<string, any>, options: SendOptions = {}): Promise<any> {
     const id = `msg_${this.messageId++}`;
     const message: Message = { id, type, payload };
+    if (options.deadlineMs) {
+      message.deadlineMs = options.deadlineMs;
+    }
 
     return new Promise((resolve, reject) => {
       this.pendingRequests.set(id, { resolve, reject });
 
+      // The bridge answers the cancelled request itself, so nothing is rejected here
+      options.signal?.addEventListener('abort', () => {
+        if (this.pendingRequests.has(id)) {
+          this.send('cancel', { requestId: id }).catch(() => {});
+        }
+      }, { once: true });
+
       // IPC Safeguard: Write framed message with length-prefix and CRC32 validation
-      // Frame format: [uint32 length][uint32 crc32][json bytes]
+      // Frame format: [magic][uint32 length][uint32 crc32][json bytes]
       const jsonBuffer = Buffer.from(JSON.stringify(message), 'utf8');
       const checksum = crc32.unsigned(jsonBuffer);
 
-      // Create frame buffer: length (4 bytes) + crc32 (4 bytes) + json
-      const frame = Buffer.alloc(8 + jsonBuffer.length);
-      frame.writeUInt32BE(jsonBuffer.length, 0); // Write length
-      frame.writeUInt32BE(checksum, 4); // Write CRC32
-      jsonBuffer.copy(frame, 8); // Copy JSON payload
+      // Create frame buffer: magic (4 bytes) + length (4 bytes) + crc32 (4 bytes) + json
+      const frame = Buffer.alloc(FRAME_HEADER_SIZE + jsonBuffer.length);
+      FRAME_MAGIC.copy(frame, 0); // Write sync marker
+      frame.writeUInt32BE(jsonBuffer.length, 4); // Write length
+      frame.writeUInt32BE(checksum, 8); // Write CRC32
+      jsonBuffer.copy(frame, FRAME_HEADER_SIZE); // Copy JSON payload
 
       this.process.stdin!.write(frame);
     });
@@ -261,6 +386,27 @@ export class BridgeConnection {
     return this.send('clickToolbarAction', { toolbarId, actionLabel });
   }
 
+  /**
+   * Heartbeat the bridge, keeping a --heartbeat-timeout watchdog satisfied
+   * @returns Round-trip time in milliseconds
+   */
+  async ping(): Promise<number> {
+    const started = Date.now();
+    await this.send('ping', {});
+    return Date.now() - started;
+  }
+
+  /**
+   * Change the bridge's log level while it runs
+   * @param level debug, info, warn, error or off
+   * @param category Only this category (e.g. widgets, events, protocol); all of them if omitted
+   * @returns The resulting level spec, e.g. "info,events=debug"
+   */
+  async setLogLevel(level: string, category?: string): Promise<string> {
+    const result = await this.send('setLogLevel', category ? { level, category } : { level });
+    return result.levels;
+  }
+
   quit(): void {
     this.send('quit', {});
     this.quitTimeout = setTimeout(() => {
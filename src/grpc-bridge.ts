@@ -86,6 +86,11 @@ interface BridgeClient extends grpc.Client {
     metadata: grpc.Metadata,
     callback: (error: grpc.ServiceError | null, response: any) => void
   ) => void;
+  Invoke: (
+    request: any,
+    metadata: grpc.Metadata,
+    callback: (error: grpc.ServiceError | null, response: any) => void
+  ) => void;
 }
 
 /**
@@ -447,6 +452,21 @@ export class GrpcBridge {
     return response.widget_ids || [];
   }
 
+  /**
+   * Send any stdio message type through the generic Invoke RPC.
+   * Resolves with the handler's result, or rejects with its error.
+   */
+  async invoke(type: string, payload: Record<string, any> = {}): Promise<any> {
+    const response: any = await this.call('Invoke', {
+      type,
+      payload: JSON.stringify(payload),
+    });
+    if (!response.success) {
+      throw new Error(response.error || `${type} failed`);
+    }
+    return response.result ? JSON.parse(response.result) : {};
+  }
+
   /**
    * Quit the application
    */
@@ -1,5 +1,10 @@
+import { createHash } from 'crypto';
+import * as crc32 from 'buffer-crc32';
 import type { BridgeConnection } from './fynebridge';
 
+// Raw bytes per upload chunk; base64 inflates this by a third, well under the 10MB frame limit
+const UPLOAD_CHUNK_SIZE = 4 * 1024 * 1024;
+
 /**
  * Manages reusable image resources to reduce data transfer
  * Resources are registered once on the Go/Fyne side and can be referenced multiple times
@@ -31,6 +36,42 @@ export class ResourceManager {
     this.registeredResources.add(name);
   }
 
+  /**
+   * Register a resource too large for a single message, streaming it in chunks
+   * @param name - Unique resource name
+   * @param data - Raw (not base64) image bytes
+   * @returns Promise that resolves when the resource is registered
+   */
+  async registerLargeResource(name: string, data: Buffer): Promise<void> {
+    if (this.registeredResources.has(name)) {
+      throw new Error(`Resource already registered: ${name}`);
+    }
+
+    const { uploadId } = await this.bridge.send('uploadBegin', {
+      target: 'resource',
+      name,
+      sha256: createHash('sha256').update(data).digest('hex')
+    });
+
+    try {
+      for (let offset = 0, index = 0; offset < data.length; offset += UPLOAD_CHUNK_SIZE, index++) {
+        const chunk = data.subarray(offset, offset + UPLOAD_CHUNK_SIZE);
+        await this.bridge.send('uploadAppend', {
+          uploadId,
+          index,
+          data: chunk.toString('base64'),
+          crc32: crc32.unsigned(chunk)
+        });
+      }
+      await this.bridge.send('uploadCommit', { uploadId });
+    } catch (err) {
+      await this.bridge.send('uploadAbort', { uploadId });
+      throw err;
+    }
+
+    this.registeredResources.add(name);
+  }
+
   /**
    * Unregister a resource to free memory on the Go/Fyne side
    * @param name - Resource name to unregister